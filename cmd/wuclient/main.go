@@ -58,6 +58,45 @@ Options:
 		"create": func() (cli.Command, error) {
 			return &command.CreateCommand{Conf: conf}, nil
 		},
+		"update": func() (cli.Command, error) {
+			return &command.UpdateCommand{Conf: conf}, nil
+		},
+		"delete": func() (cli.Command, error) {
+			return &command.DeleteCommand{Conf: conf}, nil
+		},
+		"restore": func() (cli.Command, error) {
+			return &command.RestoreCommand{Conf: conf}, nil
+		},
+		"trash": func() (cli.Command, error) {
+			return &command.TrashCommand{Conf: conf}, nil
+		},
+		"history": func() (cli.Command, error) {
+			return &command.HistoryCommand{Conf: conf}, nil
+		},
+		"diff": func() (cli.Command, error) {
+			return &command.DiffCommand{Conf: conf}, nil
+		},
+		"rollback": func() (cli.Command, error) {
+			return &command.RollbackCommand{Conf: conf}, nil
+		},
+		"search": func() (cli.Command, error) {
+			return &command.SearchCommand{Conf: conf}, nil
+		},
+		"stats": func() (cli.Command, error) {
+			return &command.StatsCommand{Conf: conf}, nil
+		},
+		"tag add": func() (cli.Command, error) {
+			return &command.TagAddCommand{Conf: conf}, nil
+		},
+		"tag remove": func() (cli.Command, error) {
+			return &command.TagRemoveCommand{Conf: conf}, nil
+		},
+		"tag list": func() (cli.Command, error) {
+			return &command.TagListCommand{Conf: conf}, nil
+		},
+		"tag rename": func() (cli.Command, error) {
+			return &command.TagRenameCommand{Conf: conf}, nil
+		},
 	}
 
 	exitStatus, err := c.Run()
@@ -1,22 +1,31 @@
 package main
 
 import (
-	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"expvar"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	"github.com/jessevdk/go-flags"
-	auth_pb "github.com/mas9612/authserver/pkg/authserver"
+	"github.com/mas9612/wrapups/pkg/auth"
 	"github.com/mas9612/wrapups/pkg/version"
 	pb "github.com/mas9612/wrapups/pkg/wrapups"
 	"github.com/mas9612/wrapups/pkg/wuserver"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var (
@@ -24,12 +33,41 @@ var (
 )
 
 type options struct {
-	Port          int    `short:"p" long:"port" description:"wrapups server port" default:"10000"`
-	ElasticAddr   string `long:"elastic-addr" default:"localhost" description:"Elasticsearch server address"`
-	ElasticPort   int    `long:"elastic-port" default:"9200" description:"Elasticsaerch server port"`
-	AuthserverURL string `long:"authserver-url" default:"authserver:10000" description:"Authserver URL"`
-	TraceLog      bool   `long:"trace" description:"Enable trace log."`
-	Version       bool   `short:"v" long:"version" description:"Print wrapups version"`
+	Port                       int           `short:"p" long:"port" description:"wrapups server port" default:"10000"`
+	ElasticAddr                string        `long:"elastic-addr" default:"localhost" description:"Elasticsearch server address"`
+	ElasticPort                int           `long:"elastic-port" default:"9200" description:"Elasticsaerch server port"`
+	ElasticScheme              string        `long:"elastic-scheme" default:"http" choice:"http" choice:"https" description:"Elasticsearch server scheme"`
+	ElasticURLs                []string      `long:"elastic-url" description:"Elasticsearch node URL like https://es1:9200. Can be specified multiple times. Overrides --elastic-addr, --elastic-port and --elastic-scheme."`
+	ElasticUsername            string        `long:"elastic-username" description:"Username of Elasticsearch basic auth."`
+	ElasticPasswordFile        string        `long:"elastic-password-file" description:"File containing the password of Elasticsearch basic auth. If not set, WRAPUPS_ELASTIC_PASSWORD environment variable is used."`
+	ElasticAPIKeyFile          string        `long:"elastic-api-key-file" description:"File containing the base64 encoded Elasticsearch API key. If not set, WRAPUPS_ELASTIC_API_KEY environment variable is used."`
+	ElasticCACert              string        `long:"elastic-ca-cert" description:"PEM encoded CA certificates to verify Elasticsearch server certificate."`
+	ElasticClientCert          string        `long:"elastic-client-cert" description:"PEM encoded client certificate for Elasticsearch."`
+	ElasticClientKey           string        `long:"elastic-client-key" description:"PEM encoded private key of the client certificate for Elasticsearch."`
+	ElasticSniff               bool          `long:"elastic-sniff" description:"Find all nodes of Elasticsearch cluster by sniffing."`
+	ElasticSniffInterval       time.Duration `long:"elastic-sniff-interval" default:"15m" description:"Interval of sniffing Elasticsearch nodes."`
+	ElasticNoHealthcheck       bool          `long:"elastic-no-healthcheck" description:"Disable health check of Elasticsearch nodes."`
+	ElasticHealthcheckInterval time.Duration `long:"elastic-healthcheck-interval" default:"60s" description:"Interval of health check of Elasticsearch nodes."`
+	ElasticRetryMaxWait        time.Duration `long:"elastic-retry-max-wait" default:"5s" description:"Maximum wait between retries of the request to Elasticsearch. 0 disables retry."`
+	ElasticStartupTimeout      time.Duration `long:"elastic-startup-timeout" default:"1m" description:"How long to wait for Elasticsearch to be available on startup."`
+	AuthProvider               string        `long:"auth-provider" default:"authserver" choice:"authserver" choice:"api-key" choice:"mtls" choice:"none" description:"How to authenticate requests. authserver validates tokens issued by authserver, api-key looks up bearer token in --auth-api-key-file, mtls uses the common name of client certificate, and none accepts any request."`
+	AuthserverURL              string        `long:"authserver-url" default:"authserver:10000" description:"Authserver URL"`
+	AuthCacheTTL               time.Duration `long:"auth-cache-ttl" default:"5m" description:"How long validated tokens are cached. 0 disables cache."`
+	AuthPublicKey              string        `long:"auth-public-key" description:"PEM encoded public keys or JWKS file of authserver. If set, tokens are verified locally without calling authserver. authserver must sign tokens with RSA or EC key (RS*, PS* or ES* algorithm)."`
+	AuthRemoteFallback         bool          `long:"auth-remote-fallback" description:"Validate tokens with authserver if no public key matches their signature. Used with --auth-public-key."`
+	AuthAPIKeyFile             string        `long:"auth-api-key-file" description:"File of API keys for api-key auth provider. Each line is API key and user separated by whitespace. Reloaded when modified."`
+	AuthDefaultUser            string        `long:"auth-default-user" default:"anonymous" description:"User of the requests without bearer token for none auth provider."`
+	TLSCert                    string        `long:"tls-cert" description:"PEM encoded server certificate. If set, wuserver serves over TLS."`
+	TLSKey                     string        `long:"tls-key" description:"PEM encoded private key of the server certificate."`
+	TLSClientCA                string        `long:"tls-client-ca" description:"PEM encoded CA certificates to verify client certificates. Required by mtls auth provider."`
+	AuthCacheSize              int           `long:"auth-cache-size" default:"10000" description:"Maximum number of cached tokens."`
+	MetricsAddr                string        `long:"metrics-addr" description:"Address to serve metrics at /debug/vars like :9100. Metrics are not served if not set."`
+	DataDir                    string        `long:"data-dir" description:"Local data directory. If set, wrapups are persisted to the directory instead of Elasticsearch."`
+	PurgePeriod                time.Duration `long:"purge-period" default:"720h" description:"Period after which deleted wrapups are purged. 0 disables purge."`
+	Admins                     []string      `long:"admin" description:"User allowed to call admin RPCs. Can be specified multiple times."`
+	TraceLog                   bool          `long:"trace" description:"Enable trace log."`
+	Dev                        bool          `long:"dev" description:"Run in development mode. Wrapups are kept in memory unless --data-dir is set, and none auth provider is used."`
+	Version                    bool          `short:"v" long:"version" description:"Print wrapups version"`
 }
 
 func main() {
@@ -72,55 +110,189 @@ func main() {
 	defer listener.Close()
 	logger.Info(fmt.Sprintf("listening on :%d", opts.Port))
 
-	wrapupsOpts := make([]wuserver.Option, 0, 5)
+	wrapupsOpts := make([]wuserver.Option, 0, 20)
 	if opts.ElasticAddr != "" {
 		wrapupsOpts = append(wrapupsOpts, wuserver.SetURL(opts.ElasticAddr))
 	}
 	if opts.ElasticPort != 9200 {
 		wrapupsOpts = append(wrapupsOpts, wuserver.SetPort(opts.ElasticPort))
 	}
+	if opts.ElasticScheme != "http" {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetScheme(opts.ElasticScheme))
+	}
+	if len(opts.ElasticURLs) > 0 {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetURLs(opts.ElasticURLs...))
+	}
+	password, err := readSecret(opts.ElasticPasswordFile, "WRAPUPS_ELASTIC_PASSWORD")
+	if err != nil {
+		logger.Fatal("failed to read Elasticsearch password", zap.Error(err))
+	}
+	if opts.ElasticUsername != "" || password != "" {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetBasicAuth(opts.ElasticUsername, password))
+	}
+	apiKey, err := readSecret(opts.ElasticAPIKeyFile, "WRAPUPS_ELASTIC_API_KEY")
+	if err != nil {
+		logger.Fatal("failed to read Elasticsearch API key", zap.Error(err))
+	}
+	if apiKey != "" {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetAPIKey(apiKey))
+	}
+	if opts.ElasticCACert != "" {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetCACert(opts.ElasticCACert))
+	}
+	if opts.ElasticClientCert != "" || opts.ElasticClientKey != "" {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetClientCert(opts.ElasticClientCert, opts.ElasticClientKey))
+	}
+	wrapupsOpts = append(wrapupsOpts,
+		wuserver.SetSniff(opts.ElasticSniff, opts.ElasticSniffInterval),
+		wuserver.SetHealthcheck(!opts.ElasticNoHealthcheck, opts.ElasticHealthcheckInterval),
+		wuserver.SetRetry(opts.ElasticRetryMaxWait),
+		wuserver.SetStartupTimeout(opts.ElasticStartupTimeout),
+	)
 	if opts.AuthserverURL != "" {
 		authserverURL = opts.AuthserverURL
 	}
 	if opts.TraceLog {
 		wrapupsOpts = append(wrapupsOpts, wuserver.SetTrace(opts.TraceLog))
 	}
-	wuServer, err := wuserver.NewWrapupsServer(logger, wrapupsOpts...)
+	wrapupsOpts = append(wrapupsOpts, wuserver.SetPurgePeriod(opts.PurgePeriod))
+	if opts.DataDir != "" {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetDataDir(opts.DataDir))
+	}
+	if len(opts.Admins) > 0 {
+		wrapupsOpts = append(wrapupsOpts, wuserver.SetAdmins(opts.Admins))
+	}
+	if opts.Dev {
+		logger.Warn("running in development mode")
+		if opts.DataDir == "" {
+			logger.Warn("wrapups are kept in memory and not persisted")
+			wrapupsOpts = append(wrapupsOpts, wuserver.SetStore(wuserver.NewMemoryStore()))
+		}
+		opts.AuthProvider = "none"
+	} else if opts.AuthProvider == "none" && len(opts.Admins) > 0 {
+		// none auth provider takes the user name from the request, so anyone could act as admin
+		logger.Fatal("--admin cannot be used with none auth provider except in development mode")
+	}
+	authenticator, err := newAuthenticator(logger, &opts)
 	if err != nil {
-		logger.Fatal("server initialization failed", zap.Error(err))
+		logger.Fatal("failed to initialize authentication", zap.Error(err))
 	}
-	grpcServer := grpc.NewServer(
+	serverAuthFunc := auth.NewAuthFunc(authenticator)
+	serverOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_auth.UnaryServerInterceptor(authFunc),
+			grpc_auth.UnaryServerInterceptor(serverAuthFunc),
 		)),
-	)
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+			grpc_auth.StreamServerInterceptor(serverAuthFunc),
+		)),
+	}
+	if opts.TLSCert != "" || opts.TLSKey != "" || opts.TLSClientCA != "" {
+		tlsConfig, err := serverTLSConfig(&opts)
+		if err != nil {
+			logger.Fatal("failed to load TLS certificates", zap.Error(err))
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else if opts.AuthProvider == "mtls" {
+		logger.Fatal("mtls auth provider requires --tls-cert, --tls-key and --tls-client-ca")
+	}
+	if opts.MetricsAddr != "" {
+		go func() {
+			// expvar registers its handler to http.DefaultServeMux
+			logger.Error("metrics server stopped", zap.Error(http.ListenAndServe(opts.MetricsAddr, nil)))
+		}()
+		logger.Info(fmt.Sprintf("serving metrics on %s", opts.MetricsAddr))
+	}
+	wuServer, err := wuserver.NewWrapupsServer(logger, wrapupsOpts...)
+	if err != nil {
+		logger.Fatal("server initialization failed", zap.Error(err))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
 	pb.RegisterWrapupsServer(grpcServer, wuServer)
+	pb.RegisterWrapupsAdminServer(grpcServer, wuServer)
 	log.Fatal(grpcServer.Serve(listener))
 }
 
-func authFunc(ctx context.Context) (context.Context, error) {
-	token, err := grpc_auth.AuthFromMD(ctx, "bearer")
+// readSecret returns the contents of file if file is set, or the value of environment variable env.
+// Secrets are not given by command line flags so that they are not shown in process list.
+func readSecret(file, env string) (string, error) {
+	if file == "" {
+		return os.Getenv(env), nil
+	}
+	b, err := ioutil.ReadFile(file)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// newAuthenticator returns the Authenticator of the auth provider selected by opts.
+func newAuthenticator(logger *zap.Logger, opts *options) (auth.Authenticator, error) {
+	switch opts.AuthProvider {
+	case "api-key":
+		if opts.AuthAPIKeyFile == "" {
+			return nil, errors.New("api-key auth provider requires --auth-api-key-file")
+		}
+		return auth.NewAPIKeyAuthenticator(logger, opts.AuthAPIKeyFile)
+	case "mtls":
+		return auth.NewMTLSAuthenticator(), nil
+	case "none":
+		logger.Warn("authentication is disabled. any request is accepted", zap.String("default_user", opts.AuthDefaultUser))
+		return auth.NewNoneAuthenticator(opts.AuthDefaultUser), nil
 	}
 
-	conn, err := grpc.Dial(authserverURL, grpc.WithInsecure())
+	validatorOpts := []auth.ValidatorOption{
+		auth.SetLogger(logger),
+		auth.SetCacheTTL(opts.AuthCacheTTL),
+		auth.SetCacheSize(opts.AuthCacheSize),
+	}
+	if opts.AuthPublicKey != "" {
+		keys, err := auth.LoadKeySet(opts.AuthPublicKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load public keys of authserver")
+		}
+		logger.Info("verifying tokens locally", zap.Int("keys", keys.Len()), zap.Strings("key_types", keys.KeyTypes()),
+			zap.Bool("remote_fallback", opts.AuthRemoteFallback))
+		validatorOpts = append(validatorOpts, auth.SetKeySet(keys), auth.SetRemoteFallback(opts.AuthRemoteFallback))
+	}
+	validator, err := auth.NewValidator(authserverURL, validatorOpts...)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "failed to connect to authserver")
 	}
-	defer conn.Close()
+	expvar.Publish("auth_token_cache", expvar.Func(func() interface{} {
+		return validator.Stats()
+	}))
+	return auth.NewTokenAuthenticator(logger, validator), nil
+}
 
-	client := auth_pb.NewAuthserverClient(conn)
-	req := &auth_pb.ValidateTokenRequest{
-		Token: token,
+// serverTLSConfig returns TLS config of wuserver.
+// If client CA is set, client certificates are verified with it, and required by mtls auth provider.
+func serverTLSConfig(opts *options) (*tls.Config, error) {
+	if opts.TLSCert == "" || opts.TLSKey == "" {
+		return nil, errors.New("both --tls-cert and --tls-key are required")
 	}
-	res, err := client.ValidateToken(ctx, req)
+	cert, err := tls.LoadX509KeyPair(opts.TLSCert, opts.TLSKey)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "failed to load server certificate")
 	}
-	if !res.Valid {
-		return nil, err
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
 	}
-
-	return context.WithValue(ctx, "user", res.User), nil
+	if opts.TLSClientCA != "" {
+		b, err := ioutil.ReadFile(opts.TLSClientCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read client CA certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errors.Errorf("no valid certificate found in \"%s\"", opts.TLSClientCA)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if opts.AuthProvider == "mtls" {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if opts.AuthProvider == "mtls" {
+		return nil, errors.New("mtls auth provider requires --tls-client-ca")
+	}
+	return tlsConfig, nil
 }
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// BackfillOwnersCommand implements backfill-owners subcommand of wuadmin.
+type BackfillOwnersCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of backfill-owners subcommand.
+func (c *BackfillOwnersCommand) Help() string {
+	helpText := `
+Usage: wuadmin backfill-owners [options]
+  Set the owner of wrapups created before ownership was recorded.
+
+Options:
+  --user  User set as the creator and last updater of wrapups which do not have them. Required.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type backfillOwnersOptions struct {
+	User string `long:"user" required:"yes" description:"User set as the creator and last updater of wrapups which do not have them."`
+}
+
+// Run runs backfill-owners subcommand and returns exit status.
+func (c *BackfillOwnersCommand) Run(args []string) int {
+	opts := backfillOwnersOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsAdminClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.BackfillOwnersRequest{
+		User: opts.User,
+	}
+	res, err := client.BackfillOwners(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to backfill owners: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%d wrapups updated\n", res.Updated)
+	return 0
+}
+
+// Synopsis returns one-line synopsis of backfill-owners subcommamd.
+func (c *BackfillOwnersCommand) Synopsis() string {
+	return "Set the owner of wrapups created before ownership was recorded."
+}
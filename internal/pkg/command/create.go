@@ -12,7 +12,9 @@ import (
 	"github.com/mas9612/wrapups/pkg/config"
 	pb "github.com/mas9612/wrapups/pkg/wrapups"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v2"
 )
 
@@ -28,20 +30,62 @@ Usage: wuclient create -f <filename>
   Create new wrapup document.
 
 Options:
-  -f, --file  Input filename. Required.
+  -f, --file         Input filename. Required.
+  --allow-duplicate  Create the document even if the one with the same title already exists.
 `
 	return strings.TrimSpace(helpText)
 }
 
+// similarPrefixLength is the number of characters at the beginning of title compared to find similar titles.
+const similarPrefixLength = 16
+
+// similarTitlePrefix returns the beginning of title used to find similar titles.
+// The whole title is not used because the title which differs only in its end, like subtitles or typos,
+// should be also found.
+func similarTitlePrefix(title string) string {
+	runes := []rune(strings.TrimSpace(title))
+	if len(runes) > similarPrefixLength {
+		runes = runes[:similarPrefixLength]
+	}
+	return strings.TrimSpace(string(runes))
+}
+
 type createOptions struct {
-	Filename string `short:"f" long:"file" required:"yes" description:"Input filename. Required."`
+	Filename       string `short:"f" long:"file" required:"yes" description:"Input filename. Required."`
+	AllowDuplicate bool   `long:"allow-duplicate" description:"Create the document even if the one with the same title already exists."`
 }
 
 type yamlData struct {
-	Title    string `yaml:"title,omitempty"`
-	Wrapup   string `yaml:"wrapup,omitempty"`
-	Comments string `yaml:"comments,omitempty"`
-	Notes    string `yaml:"notes,omitempty"`
+	Title    string   `yaml:"title,omitempty"`
+	Wrapup   string   `yaml:"wrapup,omitempty"`
+	Comments string   `yaml:"comments,omitempty"`
+	Notes    string   `yaml:"notes,omitempty"`
+	Authors  []string `yaml:"authors,omitempty"`
+	Venue    string   `yaml:"venue,omitempty"`
+	Year     int32    `yaml:"year,omitempty"`
+	DOI      string   `yaml:"doi,omitempty"`
+	ArxivID  string   `yaml:"arxiv_id,omitempty"`
+	URL      string   `yaml:"url,omitempty"`
+	Abstract string   `yaml:"abstract,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`
+}
+
+// metadata returns the bibliographic metadata written in YAML.
+// nil is returned if no metadata is written.
+func (d *yamlData) metadata() *pb.PaperMetadata {
+	m := &pb.PaperMetadata{
+		Authors:  d.Authors,
+		Venue:    d.Venue,
+		Year:     d.Year,
+		Doi:      d.DOI,
+		ArxivId:  d.ArxivID,
+		Url:      d.URL,
+		Abstract: d.Abstract,
+	}
+	if len(m.Authors) == 0 && m.Venue == "" && m.Year == 0 && m.Doi == "" && m.ArxivId == "" && m.Url == "" && m.Abstract == "" {
+		return nil
+	}
+	return m
 }
 
 // Run runs create subcommand and returns exit status.
@@ -83,14 +127,37 @@ func (c *CreateCommand) Run(args []string) int {
 		return 1
 	}
 	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+
+	// warn only; failure of suggestion must not prevent creating a document
+	suggestReq := &pb.SuggestTitlesRequest{
+		Prefix: similarTitlePrefix(data.Title),
+	}
+	if suggestRes, err := client.SuggestTitles(ctx, suggestReq); err == nil {
+		for _, suggestion := range suggestRes.Suggestions {
+			fmt.Fprintf(os.Stderr, "warning: similar title already exists: %s (%s)\n", suggestion.Id, suggestion.Title)
+		}
+	}
+
 	req := &pb.CreateWrapupRequest{
-		Title:   data.Title,
-		Wrapup:  data.Wrapup,
-		Comment: data.Comments,
-		Note:    data.Notes,
+		Title:          data.Title,
+		Wrapup:         data.Wrapup,
+		Comment:        data.Comments,
+		Note:           data.Notes,
+		Metadata:       data.metadata(),
+		Tags:           data.Tags,
+		AllowDuplicate: opts.AllowDuplicate,
 	}
 	res, err := client.CreateWrapup(ctx, req)
 	if err != nil {
+		if st := status.Convert(err); st.Code() == codes.AlreadyExists {
+			for _, detail := range st.Details() {
+				if dup, ok := detail.(*pb.DuplicateTitle); ok {
+					fmt.Fprintf(os.Stderr, "ID \"%s\" already has the same title: %s\n", dup.Id, dup.Title)
+					fmt.Fprintln(os.Stderr, "use --allow-duplicate to create it anyway")
+					return 1
+				}
+			}
+		}
 		fmt.Fprintf(os.Stderr, "failed to create document: %v\n", err)
 		return 1
 	}
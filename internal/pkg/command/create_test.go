@@ -0,0 +1,75 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+func TestSimilarTitlePrefix(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"", ""},
+		{"  BERT  ", "BERT"},
+		{"Attention Is All You Need", "Attention Is All"},
+		{"Deep Residual Learning", "Deep Residual Le"},
+		{"ああああああああああいいいいいいいいいい", "ああああああああああいいいいいい"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := similarTitlePrefix(tt.title); got != tt.want {
+				t.Errorf("similarTitlePrefix(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLDataMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		data yamlData
+		want *pb.PaperMetadata
+	}{
+		{"no metadata", yamlData{Title: "BERT", Wrapup: "pre-training", Tags: []string{"nlp"}}, nil},
+		{"empty authors", yamlData{Title: "BERT", Authors: []string{}}, nil},
+		{"only year", yamlData{Year: 2018}, &pb.PaperMetadata{Year: 2018}},
+		{
+			"all fields",
+			yamlData{
+				Authors:  []string{"Jacob Devlin", "Ming-Wei Chang"},
+				Venue:    "NAACL",
+				Year:     2019,
+				DOI:      "10.18653/v1/N19-1423",
+				ArxivID:  "1810.04805",
+				URL:      "https://arxiv.org/abs/1810.04805",
+				Abstract: "We introduce BERT.",
+			},
+			&pb.PaperMetadata{
+				Authors:  []string{"Jacob Devlin", "Ming-Wei Chang"},
+				Venue:    "NAACL",
+				Year:     2019,
+				Doi:      "10.18653/v1/N19-1423",
+				ArxivId:  "1810.04805",
+				Url:      "https://arxiv.org/abs/1810.04805",
+				Abstract: "We introduce BERT.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.data.metadata()
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("metadata() = %v, want nil", got)
+				}
+				return
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("metadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DeleteCommand implements delete subcommand.
+type DeleteCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of delete subcommand.
+func (c *DeleteCommand) Help() string {
+	helpText := `
+Usage: wuclient delete <id>
+  Move wrapup document to the trash.
+  Documents in the trash can be restored with restore subcommand until they are purged.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type deleteOptions struct {
+	Args struct {
+		ID string `description:"Wrapup document ID."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs delete subcommand and returns exit status.
+func (c *DeleteCommand) Run(args []string) int {
+	opts := deleteOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.DeleteWrapupRequest{
+		Id: opts.Args.ID,
+	}
+	res, err := client.DeleteWrapup(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to delete document: %v\n", err)
+		return 1
+	}
+	fmt.Printf("ID \"%s\" deleted\n", res.Id)
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of delete subcommamd.
+func (c *DeleteCommand) Synopsis() string {
+	return "Move wrapup document to the trash."
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// currentRevision is the special revision name which points the latest contents of wrapup document.
+const currentRevision = "current"
+
+// DiffCommand implements diff subcommand.
+type DiffCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of diff subcommand.
+func (c *DiffCommand) Help() string {
+	helpText := `
+Usage: wuclient diff <id> <rev1> <rev2>
+  Show differences of wrapup, comment and note between two revisions.
+  Revision is a revision number shown by history subcommand, or "current" for the latest contents.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type diffOptions struct {
+	Args struct {
+		ID   string `description:"Wrapup document ID."`
+		Rev1 string `description:"Old revision."`
+		Rev2 string `description:"New revision."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs diff subcommand and returns exit status.
+func (c *DiffCommand) Run(args []string) int {
+	opts := diffOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+
+	from, err := fetchRevision(ctx, client, opts.Args.ID, opts.Args.Rev1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get revision %s: %v\n", opts.Args.Rev1, err)
+		return 1
+	}
+	to, err := fetchRevision(ctx, client, opts.Args.ID, opts.Args.Rev2)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get revision %s: %v\n", opts.Args.Rev2, err)
+		return 1
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"wrapup", from.Wrapup, to.Wrapup},
+		{"comment", from.Comment, to.Comment},
+		{"note", from.Note, to.Note},
+	}
+	for _, f := range fields {
+		fromName := fmt.Sprintf("%s (revision %s)", f.name, opts.Args.Rev1)
+		toName := fmt.Sprintf("%s (revision %s)", f.name, opts.Args.Rev2)
+		fmt.Print(unifiedDiff(fromName, toName, f.from, f.to))
+	}
+
+	return 0
+}
+
+// fetchRevision returns the contents of wrapup document at given revision.
+func fetchRevision(ctx context.Context, client pb.WrapupsClient, id, revision string) (*pb.Wrapup, error) {
+	if revision == currentRevision {
+		return client.GetWrapup(ctx, &pb.GetWrapupRequest{Id: id})
+	}
+	n, err := strconv.ParseInt(revision, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("revision must be a number or \"%s\"", currentRevision)
+	}
+	res, err := client.GetWrapupRevision(ctx, &pb.GetWrapupRevisionRequest{Id: id, Revision: int32(n)})
+	if err != nil {
+		return nil, err
+	}
+	return res.Wrapup, nil
+}
+
+// Synopsis returns one-line synopsis of diff subcommamd.
+func (c *DiffCommand) Synopsis() string {
+	return "Show differences between two revisions of wrapup document."
+}
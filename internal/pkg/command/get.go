@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/jessevdk/go-flags"
 	"github.com/mas9612/wrapups/pkg/auth"
@@ -22,14 +23,18 @@ type GetCommand struct {
 // Help returns the long-form help text of get subcommand.
 func (c *GetCommand) Help() string {
 	helpText := `
-Usage: wuclient get <id>
+Usage: wuclient get [options] <id>
   Get wrapup document.
+
+Options:
+  --related  Number of related wrapups shown under the document. (default: 0)
 `
 	return strings.TrimSpace(helpText)
 }
 
 type getOptions struct {
-	Args struct {
+	Related int32 `long:"related" description:"Number of related wrapups shown under the document."`
+	Args    struct {
 		ID string `description:"Wrapup document ID."`
 	} `positional-args:"yes" required:"yes"`
 }
@@ -73,6 +78,26 @@ func (c *GetCommand) Run(args []string) int {
 
 	printWrapup(res)
 
+	if opts.Related > 0 {
+		relatedReq := &pb.FindRelatedWrapupsRequest{
+			Id:   opts.Args.ID,
+			Size: opts.Related,
+		}
+		relatedRes, err := client.FindRelatedWrapups(ctx, relatedReq)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get related documents: %v\n", err)
+			return 1
+		}
+
+		fmt.Print("\nRelated:\n")
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSCORE\tTITLE")
+		for _, r := range relatedRes.Related {
+			fmt.Fprintf(w, "%s\t%.2f\t%s\n", r.Wrapup.Id, r.Score, r.Wrapup.Title)
+		}
+		w.Flush()
+	}
+
 	return 0
 }
 
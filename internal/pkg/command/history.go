@@ -0,0 +1,93 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// HistoryCommand implements history subcommand.
+type HistoryCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of history subcommand.
+func (c *HistoryCommand) Help() string {
+	helpText := `
+Usage: wuclient history <id>
+  Show revision history of wrapup document.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type historyOptions struct {
+	Args struct {
+		ID string `description:"Wrapup document ID."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs history subcommand and returns exit status.
+func (c *HistoryCommand) Run(args []string) int {
+	opts := historyOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.ListWrapupRevisionsRequest{
+		Id: opts.Args.ID,
+	}
+	res, err := client.ListWrapupRevisions(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get revisions: %v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tREPLACED AT\tTITLE")
+	for _, revision := range res.Revisions {
+		replacedAt := "<invalid>"
+		if t, err := ptypes.Timestamp(revision.RevisionCreateTime); err == nil {
+			replacedAt = t.String()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", revision.Revision, replacedAt, revision.Wrapup.GetTitle())
+	}
+	w.Flush()
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of history subcommamd.
+func (c *HistoryCommand) Synopsis() string {
+	return "Show revision history of wrapup document."
+}
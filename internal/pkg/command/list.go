@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/jessevdk/go-flags"
 	"github.com/mas9612/wrapups/pkg/auth"
 	"github.com/mas9612/wrapups/pkg/config"
 	pb "github.com/mas9612/wrapups/pkg/wrapups"
@@ -21,14 +22,63 @@ type ListCommand struct {
 // Help returns the long-form help text of list subcommand.
 func (c *ListCommand) Help() string {
 	helpText := `
-Usage: wuclient list
+Usage: wuclient list [options]
   List wrapup documents.
+
+Options:
+  --filter      Filter expression. e.g. 'title:"attention" AND create_time>=2019-01-01 AND NOT note:draft'
+  --author      List only wrapups of the papers written by this author.
+  --venue       List only wrapups of the papers published in this venue.
+  --year        List only wrapups of the papers published in this year.
+  --tag         List only wrapups which have this tag. Can be specified multiple times.
+  --created-by  List only wrapups created by this user.
+  --mine        List only wrapups created by me.
+  --limit       Maximum number of wrapups to list. If --page is set, this is used as page size (default: 20).
+  --page        Page number to list, starting from 1. If not set, all pages are listed.
+  --sort        Sort order. One of create_time, title or relevance. Prefix "-" for descending order.
+                Multiple keys can be separated by comma. e.g. --sort=-create_time,title
 `
 	return strings.TrimSpace(helpText)
 }
 
+type listOptions struct {
+	Filter    string   `long:"filter" description:"Filter expression."`
+	Author    string   `long:"author" description:"List only wrapups of the papers written by this author."`
+	Venue     string   `long:"venue" description:"List only wrapups of the papers published in this venue."`
+	Year      int32    `long:"year" description:"List only wrapups of the papers published in this year."`
+	Tags      []string `long:"tag" description:"List only wrapups which have this tag. Can be specified multiple times."`
+	CreatedBy string   `long:"created-by" description:"List only wrapups created by this user."`
+	Mine      bool     `long:"mine" description:"List only wrapups created by me."`
+	Limit     int      `long:"limit" description:"Maximum number of wrapups to list."`
+	Page      int      `long:"page" description:"Page number to list, starting from 1."`
+	Sort      string   `long:"sort" description:"Sort order. Prefix \"-\" for descending order."`
+}
+
+// defaultListPageSize is the page size used when --page is set without --limit.
+const defaultListPageSize = 20
+
 // Run runs list subcommand and returns exit status.
 func (c *ListCommand) Run(args []string) int {
+	opts := listOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	if opts.Mine {
+		if opts.CreatedBy != "" {
+			fmt.Fprintln(os.Stderr, "--mine and --created-by cannot be used together")
+			return 1
+		}
+		opts.CreatedBy = "mine"
+	}
+
 	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
@@ -43,22 +93,74 @@ func (c *ListCommand) Run(args []string) int {
 		return 1
 	}
 	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
-	req := &pb.ListWrapupsRequest{}
-	res, err := client.ListWrapups(ctx, req)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to get response from wuserver: %v\n", err)
+	if opts.Limit < 0 || opts.Page < 0 {
+		fmt.Fprintln(os.Stderr, "--limit and --page must not be negative")
 		return 1
 	}
+	pageSize := opts.Limit
+	if opts.Page > 0 && pageSize == 0 {
+		pageSize = defaultListPageSize
+	}
+	req := &pb.ListWrapupsRequest{
+		Filter:    opts.Filter,
+		Author:    opts.Author,
+		Venue:     opts.Venue,
+		Year:      opts.Year,
+		Tags:      opts.Tags,
+		CreatedBy: opts.CreatedBy,
+		PageSize:  int32(pageSize),
+		OrderBy:   sortToOrderBy(opts.Sort),
+	}
+
+	printed := 0
+	for page := 1; ; page++ {
+		res, err := client.ListWrapups(ctx, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get response from wuserver: %v\n", err)
+			return 1
+		}
+		if page == 1 {
+			fmt.Printf("Count: %d\n", res.TotalSize)
+		}
+
+		if opts.Page == 0 || opts.Page == page {
+			for _, wrapup := range res.Wrapups {
+				if opts.Limit > 0 && printed >= opts.Limit {
+					break
+				}
+				printWrapup(wrapup)
+				fmt.Print("\n")
+				printed++
+			}
+		}
 
-	fmt.Printf("Count: %d\n", res.Count)
-	for _, wrapup := range res.Wrapups {
-		printWrapup(wrapup)
-		fmt.Print("\n")
+		if res.NextPageToken == "" || (opts.Page > 0 && page >= opts.Page) || (opts.Limit > 0 && printed >= opts.Limit) {
+			break
+		}
+		req.PageToken = res.NextPageToken
 	}
 
 	return 0
 }
 
+// sortToOrderBy converts sort flag like "-create_time,title" to order_by like "create_time desc, title asc".
+func sortToOrderBy(sort string) string {
+	if sort == "" {
+		return ""
+	}
+	keys := strings.Split(sort, ",")
+	clauses := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if strings.HasPrefix(key, "-") {
+			clauses = append(clauses, strings.TrimPrefix(key, "-")+" desc")
+		} else {
+			clauses = append(clauses, strings.TrimPrefix(key, "+")+" asc")
+		}
+	}
+	return strings.Join(clauses, ", ")
+}
+
 // Synopsis returns one-line synopsis of list subcommamd.
 func (c *ListCommand) Synopsis() string {
 	return "List wrapup documents."
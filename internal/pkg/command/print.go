@@ -2,8 +2,10 @@ package command
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	pb "github.com/mas9612/wrapups/pkg/wrapups"
 )
 
@@ -13,10 +15,52 @@ func printWrapup(doc *pb.Wrapup) {
 	fmt.Printf("Wrapup: %s\n", doc.Wrapup)
 	fmt.Printf("Comment: %s\n", doc.Comment)
 	fmt.Printf("Note: %s\n", doc.Note)
-	t, err := ptypes.Timestamp(doc.CreateTime)
+	if m := doc.Metadata; m != nil {
+		if len(m.Authors) > 0 {
+			fmt.Printf("Authors: %s\n", strings.Join(m.Authors, ", "))
+		}
+		if m.Venue != "" {
+			fmt.Printf("Venue: %s\n", m.Venue)
+		}
+		if m.Year != 0 {
+			fmt.Printf("Year: %d\n", m.Year)
+		}
+		if m.Doi != "" {
+			fmt.Printf("DOI: %s\n", m.Doi)
+		}
+		if m.ArxivId != "" {
+			fmt.Printf("arXiv: %s\n", m.ArxivId)
+		}
+		if m.Url != "" {
+			fmt.Printf("URL: %s\n", m.Url)
+		}
+		if m.Abstract != "" {
+			fmt.Printf("Abstract: %s\n", m.Abstract)
+		}
+	}
+	if len(doc.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(doc.Tags, ", "))
+	}
+	if doc.CreatedBy != "" {
+		fmt.Printf("CreatedBy: %s\n", doc.CreatedBy)
+	}
+	printTimestamp("CreateTime", doc.CreateTime)
+	if doc.UpdatedBy != "" {
+		fmt.Printf("UpdatedBy: %s\n", doc.UpdatedBy)
+	}
+	if doc.UpdateTime != nil {
+		printTimestamp("UpdateTime", doc.UpdateTime)
+	}
+	if doc.DeleteTime != nil {
+		printTimestamp("DeleteTime", doc.DeleteTime)
+	}
+}
+
+func printTimestamp(name string, ts *timestamp.Timestamp) {
+	t, err := ptypes.Timestamp(ts)
 	if err != nil {
-		fmt.Printf("CreateTime: <invalid>\n")
+		fmt.Printf("%s: <invalid>\n", name)
 	} else {
-		fmt.Printf("CreateTime: %s\n", t.String())
+		fmt.Printf("%s: %s\n", name, t.String())
 	}
 }
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ReindexCommand implements reindex subcommand of wuadmin.
+type ReindexCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of reindex subcommand.
+func (c *ReindexCommand) Help() string {
+	helpText := `
+Usage: wuadmin reindex [options]
+  Copy all wrapups to new index with current mapping and switch to it.
+  Writes to wrapups server are held until the index is switched.
+
+Options:
+  --delete-old-index  Delete old index after switched. By default, old index is kept for rollback.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type reindexOptions struct {
+	DeleteOldIndex bool `long:"delete-old-index" description:"Delete old index after switched."`
+}
+
+// Run runs reindex subcommand and returns exit status.
+func (c *ReindexCommand) Run(args []string) int {
+	opts := reindexOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsAdminClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.ReindexWrapupsRequest{
+		DeleteOldIndex: opts.DeleteOldIndex,
+	}
+	stream, err := client.ReindexWrapups(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to reindex: %v\n", err)
+		return 1
+	}
+	for {
+		progress, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to reindex: %v\n", err)
+			return 1
+		}
+		printReindexProgress(progress)
+	}
+	return 0
+}
+
+// printReindexProgress prints one line for each progress.
+func printReindexProgress(p *pb.ReindexProgress) {
+	switch p.Step {
+	case pb.ReindexProgress_CREATE_INDEX:
+		fmt.Printf("creating index %s\n", p.TargetIndex)
+	case pb.ReindexProgress_COPY:
+		fmt.Printf("copying documents from %s: %d/%d\n", p.SourceIndex, p.Copied, p.Total)
+	case pb.ReindexProgress_VERIFY:
+		fmt.Printf("verifying %d documents\n", p.Total)
+	case pb.ReindexProgress_SWITCH_ALIAS:
+		fmt.Printf("switching alias from %s to %s\n", p.SourceIndex, p.TargetIndex)
+	case pb.ReindexProgress_DELETE_OLD_INDEX:
+		fmt.Printf("deleting index %s\n", p.SourceIndex)
+	case pb.ReindexProgress_DONE:
+		fmt.Printf("done. %d documents reindexed to %s\n", p.Total, p.TargetIndex)
+	}
+}
+
+// Synopsis returns one-line synopsis of reindex subcommamd.
+func (c *ReindexCommand) Synopsis() string {
+	return "Copy all wrapups to new index and switch to it."
+}
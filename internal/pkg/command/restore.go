@@ -0,0 +1,81 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RestoreCommand implements restore subcommand.
+type RestoreCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of restore subcommand.
+func (c *RestoreCommand) Help() string {
+	helpText := `
+Usage: wuclient restore <id>
+  Restore wrapup document from the trash.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type restoreOptions struct {
+	Args struct {
+		ID string `description:"Wrapup document ID."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs restore subcommand and returns exit status.
+func (c *RestoreCommand) Run(args []string) int {
+	opts := restoreOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.UndeleteWrapupRequest{
+		Id: opts.Args.ID,
+	}
+	res, err := client.UndeleteWrapup(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to restore document: %v\n", err)
+		return 1
+	}
+	fmt.Printf("ID \"%s\" restored\n", res.Id)
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of restore subcommamd.
+func (c *RestoreCommand) Synopsis() string {
+	return "Restore wrapup document from the trash."
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RollbackCommand implements rollback subcommand.
+type RollbackCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of rollback subcommand.
+func (c *RollbackCommand) Help() string {
+	helpText := `
+Usage: wuclient rollback <id> <revision>
+  Restore wrapup document to the specified revision.
+  The contents before rollback are kept as a new revision.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type rollbackOptions struct {
+	Args struct {
+		ID       string `description:"Wrapup document ID."`
+		Revision int32  `description:"Revision number to restore."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs rollback subcommand and returns exit status.
+func (c *RollbackCommand) Run(args []string) int {
+	opts := rollbackOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.RollbackWrapupRequest{
+		Id:       opts.Args.ID,
+		Revision: opts.Args.Revision,
+	}
+	res, err := client.RollbackWrapup(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to rollback document: %v\n", err)
+		return 1
+	}
+	fmt.Printf("ID \"%s\" restored to revision %d\n", res.Id, opts.Args.Revision)
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of rollback subcommamd.
+func (c *RollbackCommand) Synopsis() string {
+	return "Restore wrapup document to the specified revision."
+}
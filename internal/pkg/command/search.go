@@ -0,0 +1,126 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+)
+
+// SearchCommand implements search subcommand.
+type SearchCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of search subcommand.
+func (c *SearchCommand) Help() string {
+	helpText := `
+Usage: wuclient search [options] <query>
+  Search wrapup documents by full-text query across title, authors, wrapup, abstract, comment and note.
+
+Options:
+  --limit  Maximum number of results to show. (default: 10)
+`
+	return strings.TrimSpace(helpText)
+}
+
+type searchOptions struct {
+	Limit int `long:"limit" default:"10" description:"Maximum number of results to show."`
+	Args  struct {
+		Query []string `description:"Search query."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs search subcommand and returns exit status.
+func (c *SearchCommand) Run(args []string) int {
+	opts := searchOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.SearchWrapupsRequest{
+		Query:    strings.Join(opts.Args.Query, " "),
+		PageSize: int32(opts.Limit),
+	}
+	res, err := client.SearchWrapups(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to search documents: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Count: %d\n", res.TotalSize)
+	for _, result := range res.Results {
+		fmt.Printf("\nID: %s\n", result.Wrapup.Id)
+		fmt.Printf("Title: %s\n", result.Wrapup.Title)
+		fmt.Printf("Score: %.3f\n", result.Score)
+		for _, hl := range result.Highlights {
+			for _, fragment := range hl.Fragments {
+				fmt.Printf("  %s: %s\n", hl.Field, colorHighlight(fragment))
+			}
+		}
+	}
+
+	return 0
+}
+
+// colorHighlight replaces the highlight tags in fragment with terminal colors.
+func colorHighlight(fragment string) string {
+	matched := color.New(color.FgRed, color.Bold).SprintFunc()
+	var b strings.Builder
+	for {
+		start := strings.Index(fragment, highlightPreTag)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(fragment[start:], highlightPostTag)
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(fragment[:start])
+		b.WriteString(matched(fragment[start+len(highlightPreTag) : end]))
+		fragment = fragment[end+len(highlightPostTag):]
+	}
+	b.WriteString(fragment)
+	// fragments may contain newlines of original text
+	return strings.Replace(b.String(), "\n", " ", -1)
+}
+
+// Synopsis returns one-line synopsis of search subcommamd.
+func (c *SearchCommand) Synopsis() string {
+	return "Search wrapup documents."
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// StatsCommand implements stats subcommand.
+type StatsCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of stats subcommand.
+func (c *StatsCommand) Help() string {
+	helpText := `
+Usage: wuclient stats [options]
+  Show statistics of wrapup documents.
+
+Options:
+  --filter  Filter expression to aggregate only matched wrapups. Same syntax as list subcommand.
+  --terms   Number of significant terms shown for each month. (default: 5)
+`
+	return strings.TrimSpace(helpText)
+}
+
+type statsOptions struct {
+	Filter string `long:"filter" description:"Filter expression."`
+	Terms  int32  `long:"terms" default:"5" description:"Number of significant terms shown for each month."`
+}
+
+// Run runs stats subcommand and returns exit status.
+func (c *StatsCommand) Run(args []string) int {
+	opts := statsOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.AggregateWrapupsRequest{
+		Filter:               opts.Filter,
+		SignificantTermsSize: opts.Terms,
+	}
+	res, err := client.AggregateWrapups(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get statistics: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Total: %d\n\n", res.TotalSize)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "MONTH\tCOUNT\tSIGNIFICANT TERMS")
+	for _, period := range res.Monthly {
+		month := "<invalid>"
+		if t, err := ptypes.Timestamp(period.StartTime); err == nil {
+			month = t.Format("2006-01")
+		}
+		terms := make([]string, 0, len(period.SignificantTerms))
+		for _, term := range period.SignificantTerms {
+			terms = append(terms, term.Key)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", month, period.Count, strings.Join(terms, ", "))
+	}
+	w.Flush()
+	fmt.Print("\n")
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tCOUNT")
+	for _, tag := range res.Tags {
+		fmt.Fprintf(w, "%s\t%d\n", tag.Key, tag.Count)
+	}
+	w.Flush()
+	fmt.Print("\n")
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "WRAPUP LENGTH\tCOUNT")
+	for _, bucket := range res.WrapupLengths {
+		length := fmt.Sprintf("%d-%d", bucket.From, bucket.To-1)
+		if bucket.To == 0 {
+			length = fmt.Sprintf("%d-", bucket.From)
+		}
+		fmt.Fprintf(w, "%s\t%d\n", length, bucket.Count)
+	}
+	w.Flush()
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of stats subcommamd.
+func (c *StatsCommand) Synopsis() string {
+	return "Show statistics of wrapup documents."
+}
@@ -0,0 +1,263 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TagAddCommand implements tag add subcommand.
+type TagAddCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of tag add subcommand.
+func (c *TagAddCommand) Help() string {
+	helpText := `
+Usage: wuclient tag add <id> <tag>
+  Add tag to wrapup document.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run runs tag add subcommand and returns exit status.
+func (c *TagAddCommand) Run(args []string) int {
+	return runTagEdit(c.Conf, args, func(tags []string, tag string) []string {
+		for _, t := range tags {
+			if t == tag {
+				return tags
+			}
+		}
+		return append(tags, tag)
+	})
+}
+
+// Synopsis returns one-line synopsis of tag add subcommamd.
+func (c *TagAddCommand) Synopsis() string {
+	return "Add tag to wrapup document."
+}
+
+// TagRemoveCommand implements tag remove subcommand.
+type TagRemoveCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of tag remove subcommand.
+func (c *TagRemoveCommand) Help() string {
+	helpText := `
+Usage: wuclient tag remove <id> <tag>
+  Remove tag from wrapup document.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run runs tag remove subcommand and returns exit status.
+func (c *TagRemoveCommand) Run(args []string) int {
+	return runTagEdit(c.Conf, args, func(tags []string, tag string) []string {
+		removed := make([]string, 0, len(tags))
+		for _, t := range tags {
+			if t != tag {
+				removed = append(removed, t)
+			}
+		}
+		return removed
+	})
+}
+
+// Synopsis returns one-line synopsis of tag remove subcommamd.
+func (c *TagRemoveCommand) Synopsis() string {
+	return "Remove tag from wrapup document."
+}
+
+type tagEditOptions struct {
+	Args struct {
+		ID  string `description:"Wrapup document ID."`
+		Tag string `description:"Tag name."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// runTagEdit replaces the tags of wrapup document with the result of edit.
+func runTagEdit(conf *config.Config, args []string, edit func(tags []string, tag string) []string) int {
+	opts := tagEditOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	doc, err := client.GetWrapup(ctx, &pb.GetWrapupRequest{Id: opts.Args.ID})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get document: %v\n", err)
+		return 1
+	}
+
+	req := &pb.UpdateWrapupRequest{
+		Wrapup: &pb.Wrapup{
+			Id:   opts.Args.ID,
+			Tags: edit(doc.Tags, strings.TrimSpace(opts.Args.Tag)),
+			// tags are edited based on doc, so fail if others changed them meanwhile
+			Version: doc.Version,
+		},
+		UpdateMask: &field_mask.FieldMask{
+			Paths: []string{"tags"},
+		},
+	}
+	res, err := client.UpdateWrapup(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to update document: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Tags: %s\n", strings.Join(res.Tags, ", "))
+
+	return 0
+}
+
+// TagListCommand implements tag list subcommand.
+type TagListCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of tag list subcommand.
+func (c *TagListCommand) Help() string {
+	helpText := `
+Usage: wuclient tag list
+  List all tags with the number of wrapup documents.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run runs tag list subcommand and returns exit status.
+func (c *TagListCommand) Run(args []string) int {
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	res, err := client.ListTags(ctx, &pb.ListTagsRequest{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get tags: %v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tCOUNT")
+	for _, tag := range res.Tags {
+		fmt.Fprintf(w, "%s\t%d\n", tag.Name, tag.Count)
+	}
+	w.Flush()
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of tag list subcommamd.
+func (c *TagListCommand) Synopsis() string {
+	return "List all tags."
+}
+
+// TagRenameCommand implements tag rename subcommand.
+type TagRenameCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of tag rename subcommand.
+func (c *TagRenameCommand) Help() string {
+	helpText := `
+Usage: wuclient tag rename <tag> <new-tag>
+  Rename tag in all wrapup documents.
+  If new-tag is already used, two tags are merged.
+  Admin privilege is required.
+`
+	return strings.TrimSpace(helpText)
+}
+
+type tagRenameOptions struct {
+	Args struct {
+		Tag    string `description:"Current tag name."`
+		NewTag string `description:"New tag name."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs tag rename subcommand and returns exit status.
+func (c *TagRenameCommand) Run(args []string) int {
+	opts := tagRenameOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.RenameTagRequest{
+		Tag:    opts.Args.Tag,
+		NewTag: opts.Args.NewTag,
+	}
+	res, err := client.RenameTag(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to rename tag: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%d documents updated\n", res.Updated)
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of tag rename subcommamd.
+func (c *TagRenameCommand) Synopsis() string {
+	return "Rename tag in all wrapup documents."
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TrashCommand implements trash subcommand.
+type TrashCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of trash subcommand.
+func (c *TrashCommand) Help() string {
+	helpText := `
+Usage: wuclient trash
+  List wrapup documents in the trash.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Run runs trash subcommand and returns exit status.
+func (c *TrashCommand) Run(args []string) int {
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.ListDeletedWrapupsRequest{}
+	for page := 1; ; page++ {
+		res, err := client.ListDeletedWrapups(ctx, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get response from wuserver: %v\n", err)
+			return 1
+		}
+		if page == 1 {
+			fmt.Printf("Count: %d\n", res.TotalSize)
+		}
+		for _, wrapup := range res.Wrapups {
+			printWrapup(wrapup)
+			fmt.Print("\n")
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of trash subcommamd.
+func (c *TrashCommand) Synopsis() string {
+	return "List wrapup documents in the trash."
+}
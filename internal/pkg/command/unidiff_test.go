@@ -0,0 +1,157 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+// lines joins lines with newline, terminating the last one.
+func lines(l ...string) string {
+	if len(l) == 0 {
+		return ""
+	}
+	return strings.Join(l, "\n") + "\n"
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "empty input",
+			from: "",
+			to:   "",
+			want: "",
+		},
+		{
+			name: "no difference",
+			from: lines("a", "b", "c"),
+			to:   lines("a", "b", "c"),
+			want: "",
+		},
+		{
+			name: "insert into empty",
+			from: "",
+			to:   lines("a", "b"),
+			want: lines(
+				"--- old",
+				"+++ new",
+				"@@ -0,0 +1,2 @@",
+				"+a",
+				"+b",
+			),
+		},
+		{
+			name: "delete all",
+			from: lines("a", "b"),
+			to:   "",
+			want: lines(
+				"--- old",
+				"+++ new",
+				"@@ -1,2 +0,0 @@",
+				"-a",
+				"-b",
+			),
+		},
+		{
+			name: "insert only",
+			from: lines("1", "2", "3", "4", "5"),
+			to:   lines("1", "2", "x", "3", "4", "5"),
+			want: lines(
+				"--- old",
+				"+++ new",
+				"@@ -1,5 +1,6 @@",
+				" 1",
+				" 2",
+				"+x",
+				" 3",
+				" 4",
+				" 5",
+			),
+		},
+		{
+			name: "delete only",
+			from: lines("1", "2", "3", "4", "5", "6", "7", "8"),
+			to:   lines("1", "2", "3", "4", "6", "7", "8"),
+			want: lines(
+				"--- old",
+				"+++ new",
+				"@@ -2,7 +2,6 @@",
+				" 2",
+				" 3",
+				" 4",
+				"-5",
+				" 6",
+				" 7",
+				" 8",
+			),
+		},
+		{
+			name: "changes within context are merged",
+			from: lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10"),
+			to:   lines("1", "x", "3", "4", "5", "6", "7", "y", "9", "10"),
+			want: lines(
+				"--- old",
+				"+++ new",
+				"@@ -1,10 +1,10 @@",
+				" 1",
+				"-2",
+				"+x",
+				" 3",
+				" 4",
+				" 5",
+				" 6",
+				" 7",
+				"-8",
+				"+y",
+				" 9",
+				" 10",
+			),
+		},
+		{
+			name: "distant changes are separate hunks",
+			from: lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"),
+			to:   lines("x", "2", "3", "4", "5", "6", "7", "8", "9", "10", "y"),
+			want: lines(
+				"--- old",
+				"+++ new",
+				"@@ -1,4 +1,4 @@",
+				"-1",
+				"+x",
+				" 2",
+				" 3",
+				" 4",
+				"@@ -8,4 +8,4 @@",
+				" 8",
+				" 9",
+				" 10",
+				"-11",
+				"+y",
+			),
+		},
+		{
+			name: "last line without newline",
+			from: "a\nb",
+			to:   "a\nc",
+			want: lines(
+				"--- old",
+				"+++ new",
+				"@@ -1,2 +1,2 @@",
+				" a",
+				"-b",
+				"+c",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("old", "new", tt.from, tt.to)
+			if got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
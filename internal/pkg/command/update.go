@@ -0,0 +1,161 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/mas9612/wrapups/pkg/auth"
+	"github.com/mas9612/wrapups/pkg/config"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// UpdateCommand implements update subcommand.
+type UpdateCommand struct {
+	Conf *config.Config
+}
+
+// Help returns the long-form help text of update subcommand.
+func (c *UpdateCommand) Help() string {
+	helpText := `
+Usage: wuclient update -f <filename> <id>
+  Update existing wrapup document.
+  Only the fields written in input file are updated.
+  To clear a field, write it with empty value like 'notes: ""'.
+
+Options:
+  -f, --file         Input filename. Required.
+  --allow-duplicate  Change the title even if the document with the new title already exists.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// updateFields maps the keys of input file to the paths of update mask.
+var updateFields = []struct {
+	key  string
+	path string
+}{
+	{"title", "title"},
+	{"wrapup", "wrapup"},
+	{"comments", "comment"},
+	{"notes", "note"},
+	{"authors", "metadata.authors"},
+	{"venue", "metadata.venue"},
+	{"year", "metadata.year"},
+	{"doi", "metadata.doi"},
+	{"arxiv_id", "metadata.arxiv_id"},
+	{"url", "metadata.url"},
+	{"abstract", "metadata.abstract"},
+	{"tags", "tags"},
+}
+
+type updateOptions struct {
+	Filename       string `short:"f" long:"file" required:"yes" description:"Input filename. Required."`
+	AllowDuplicate bool   `long:"allow-duplicate" description:"Change the title even if the document with the new title already exists."`
+	Args           struct {
+		ID string `description:"Wrapup document ID."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Run runs update subcommand and returns exit status.
+func (c *UpdateCommand) Run(args []string) int {
+	opts := updateOptions{}
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	if _, err := parser.ParseArgs(args); err != nil {
+		flagsErr := err.(*flags.Error)
+		if flagsErr.Type == flags.ErrHelp {
+			fmt.Printf("%s\n", flagsErr.Message)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "failed to parse command line flags: %s", err.Error())
+		return 1
+	}
+
+	conn, err := grpc.Dial(c.Conf.WuserverURL, grpc.WithInsecure())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to gRPC server: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewWrapupsClient(conn)
+
+	b, err := ioutil.ReadFile(opts.Filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read file: %v\n", err)
+		return 1
+	}
+	var data yamlData
+	if err := yaml.Unmarshal(b, &data); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse YAML file: %v\n", err)
+		return 1
+	}
+
+	wrapup := &pb.Wrapup{
+		Id:       opts.Args.ID,
+		Title:    data.Title,
+		Wrapup:   data.Wrapup,
+		Comment:  data.Comments,
+		Note:     data.Notes,
+		Metadata: data.metadata(),
+		Tags:     data.Tags,
+	}
+	// keys are checked separately because empty values cannot be distinguished from missing ones in data
+	var keys map[string]interface{}
+	if err := yaml.Unmarshal(b, &keys); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse YAML file: %v\n", err)
+		return 1
+	}
+	mask := &field_mask.FieldMask{}
+	for _, field := range updateFields {
+		if _, ok := keys[field.key]; ok {
+			mask.Paths = append(mask.Paths, field.path)
+		}
+	}
+	if len(mask.Paths) == 0 {
+		fmt.Fprintln(os.Stderr, "no fields to update")
+		return 1
+	}
+
+	token, err := auth.Token(c.Conf.AuthserverURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "auth error: %s\n", err.Error())
+		return 1
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", fmt.Sprintf("bearer %s", token)))
+	req := &pb.UpdateWrapupRequest{
+		Wrapup:         wrapup,
+		UpdateMask:     mask,
+		AllowDuplicate: opts.AllowDuplicate,
+	}
+	res, err := client.UpdateWrapup(ctx, req)
+	if err != nil {
+		if st := status.Convert(err); st.Code() == codes.AlreadyExists {
+			for _, detail := range st.Details() {
+				if dup, ok := detail.(*pb.DuplicateTitle); ok {
+					fmt.Fprintf(os.Stderr, "ID \"%s\" already has the same title: %s\n", dup.Id, dup.Title)
+					fmt.Fprintln(os.Stderr, "use --allow-duplicate to change it anyway")
+					return 1
+				}
+			}
+		}
+		fmt.Fprintf(os.Stderr, "failed to update document: %v\n", err)
+		return 1
+	}
+	fmt.Printf("ID \"%s\" updated\n", res.Id)
+
+	return 0
+}
+
+// Synopsis returns one-line synopsis of update subcommamd.
+func (c *UpdateCommand) Synopsis() string {
+	return "Update existing wrapup document."
+}
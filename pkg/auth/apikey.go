@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// apiKeyReloadInterval is the interval of checking whether API key file is modified.
+const apiKeyReloadInterval = 10 * time.Second
+
+// APIKeyAuthenticator authenticates requests with the API key given as bearer token.
+// API keys are loaded from the file, and reloaded when the file is modified.
+type APIKeyAuthenticator struct {
+	logger *zap.Logger
+	file   string
+
+	mu sync.RWMutex
+	// keys maps the hash of API key to the user.
+	// Keys are not kept in memory as is, same as tokenCache.
+	keys    map[tokenKey]string
+	modTime time.Time
+	size    int64
+
+	done chan struct{}
+}
+
+// NewAPIKeyAuthenticator loads API keys from file and returns new APIKeyAuthenticator.
+// Each line of the file is an API key and its user separated by whitespace.
+// Empty lines and lines starting with # are ignored.
+// The file is checked periodically until Close is called, and reloaded if modified.
+// If the modified file is invalid, previous keys are kept.
+func NewAPIKeyAuthenticator(logger *zap.Logger, file string) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{
+		logger: logger,
+		file:   file,
+		done:   make(chan struct{}),
+	}
+	if _, err := a.reload(); err != nil {
+		return nil, err
+	}
+	go a.watch()
+	return a, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	key, err := BearerToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	a.mu.RLock()
+	user, ok := a.keys[sha256.Sum256([]byte(key))]
+	a.mu.RUnlock()
+	if !ok {
+		return "", unauthenticated(pb.AuthenticationFailure_INVALID_TOKEN, "invalid API key")
+	}
+	return user, nil
+}
+
+// Close stops reloading API key file.
+func (a *APIKeyAuthenticator) Close() error {
+	close(a.done)
+	return nil
+}
+
+func (a *APIKeyAuthenticator) watch() {
+	ticker := time.NewTicker(apiKeyReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+		}
+		reloaded, err := a.reload()
+		if err != nil {
+			a.logger.Error("failed to reload API key file. previous keys are kept", zap.Error(err))
+		} else if reloaded {
+			a.logger.Info("API key file reloaded", zap.String("file", a.file))
+		}
+	}
+}
+
+// reload loads API key file if it is modified since last load, and reports whether it is loaded.
+func (a *APIKeyAuthenticator) reload() (bool, error) {
+	info, err := os.Stat(a.file)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to stat API key file")
+	}
+	a.mu.RLock()
+	modified := !info.ModTime().Equal(a.modTime) || info.Size() != a.size
+	a.mu.RUnlock()
+	if !modified {
+		return false, nil
+	}
+
+	keys, err := loadAPIKeys(a.file)
+	if err != nil {
+		return false, err
+	}
+	a.mu.Lock()
+	a.keys = keys
+	a.modTime = info.ModTime()
+	a.size = info.Size()
+	a.mu.Unlock()
+	return true, nil
+}
+
+// loadAPIKeys parses API key file.
+func loadAPIKeys(file string) (map[tokenKey]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open API key file")
+	}
+	defer f.Close()
+
+	keys := make(map[tokenKey]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(string(line))
+		if len(fields) != 2 {
+			// do not include the line in the message not to leak the key
+			return nil, errors.Errorf("invalid API key file \"%s\": line %d must be API key and user", file, n)
+		}
+		key := sha256.Sum256([]byte(fields[0]))
+		if _, ok := keys[key]; ok {
+			return nil, errors.Errorf("invalid API key file \"%s\": line %d has duplicated API key", file, n)
+		}
+		keys[key] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read API key file")
+	}
+	return keys, nil
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+)
+
+// writeAPIKeys writes API key file and sets its modification time to modTime,
+// so that the modification is detected regardless of the resolution of file system timestamps.
+func writeAPIKeys(t *testing.T, file, contents string, modTime time.Time) {
+	t.Helper()
+	if err := ioutil.WriteFile(file, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPIKeyAuthenticatorReload(t *testing.T) {
+	const initial = "# comment\nkey1 alice\n\nkey2 bob\n"
+	tests := []struct {
+		name string
+		// contents of API key file after modification. the file is removed if nil.
+		contents *string
+		reloaded bool
+		wantErr  bool
+		// users of key1, key2 and key3 after reload. empty means the key is invalid.
+		want [3]string
+	}{
+		{
+			name:     "key added",
+			contents: stringPtr(initial + "key3 carol\n"),
+			reloaded: true,
+			want:     [3]string{"alice", "bob", "carol"},
+		},
+		{
+			name:     "key revoked",
+			contents: stringPtr("key1 alice\n"),
+			reloaded: true,
+			want:     [3]string{"alice", "", ""},
+		},
+		{
+			name:     "not modified",
+			contents: stringPtr(initial),
+			want:     [3]string{"alice", "bob", ""},
+		},
+		{
+			name:     "malformed",
+			contents: stringPtr("key1 alice\nkey3\n"),
+			wantErr:  true,
+			want:     [3]string{"alice", "bob", ""},
+		},
+		{
+			name:     "duplicated key",
+			contents: stringPtr("key1 alice\nkey1 carol\n"),
+			wantErr:  true,
+			want:     [3]string{"alice", "bob", ""},
+		},
+		{
+			name:    "missing",
+			wantErr: true,
+			want:    [3]string{"alice", "bob", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "wrapups-apikey")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			file := filepath.Join(dir, "apikeys")
+			loaded := time.Now().Add(-time.Hour)
+			writeAPIKeys(t, file, initial, loaded)
+
+			a, err := NewAPIKeyAuthenticator(zap.NewNop(), file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer a.Close()
+
+			if tt.contents == nil {
+				if err := os.Remove(file); err != nil {
+					t.Fatal(err)
+				}
+			} else if *tt.contents == initial {
+				writeAPIKeys(t, file, initial, loaded)
+			} else {
+				writeAPIKeys(t, file, *tt.contents, loaded.Add(time.Minute))
+			}
+			reloaded, err := a.reload()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reload returns error %v, want error: %v", err, tt.wantErr)
+			}
+			if reloaded != tt.reloaded {
+				t.Errorf("reloaded = %v, want %v", reloaded, tt.reloaded)
+			}
+
+			for i, key := range []string{"key1", "key2", "key3"} {
+				user, err := a.Authenticate(bearerContext(key))
+				if tt.want[i] == "" {
+					if reason := failureReason(t, err); reason != pb.AuthenticationFailure_INVALID_TOKEN {
+						t.Errorf("reason for %s = %v, want INVALID_TOKEN", key, reason)
+					}
+					continue
+				}
+				if err != nil || user != tt.want[i] {
+					t.Errorf("Authenticate(%s) = (%q, %v), want (%q, nil)", key, user, err, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthenticatorMissingToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wrapups-apikey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "apikeys")
+	writeAPIKeys(t, file, "key1 alice\n", time.Now())
+
+	a, err := NewAPIKeyAuthenticator(zap.NewNop(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	_, err = a.Authenticate(context.Background())
+	if reason := failureReason(t, err); reason != pb.AuthenticationFailure_MISSING_TOKEN {
+		t.Errorf("reason = %v, want MISSING_TOKEN", reason)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -85,6 +85,6 @@ func Token(url string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "failed to save token")
 	}
-	fmt.Fprintf(tokenFile, token.Token)
+	fmt.Fprint(tokenFile, token.Token)
 	return token.Token, nil
 }
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Authenticator authenticates incoming requests.
+type Authenticator interface {
+	// Authenticate returns the user of the request.
+	// Returned error must be gRPC status:
+	// Unauthenticated if the credential is missing or invalid,
+	// and Unavailable if the credential cannot be verified temporarily.
+	Authenticate(ctx context.Context) (string, error)
+}
+
+// NewAuthFunc returns the function which stores the user authenticated by authenticator to the context.
+// The user is taken by UserFromContext in handlers.
+func NewAuthFunc(authenticator Authenticator) grpc_auth.AuthFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		user, err := authenticator.Authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewContext(ctx, user), nil
+	}
+}
+
+// TokenAuthenticator authenticates requests with bearer token issued by authserver.
+type TokenAuthenticator struct {
+	logger    *zap.Logger
+	validator *Validator
+}
+
+// NewTokenAuthenticator returns new TokenAuthenticator which validates tokens with validator.
+func NewTokenAuthenticator(logger *zap.Logger, validator *Validator) *TokenAuthenticator {
+	return &TokenAuthenticator{
+		logger:    logger,
+		validator: validator,
+	}
+}
+
+// Authenticate implements Authenticator.
+// Missing, invalid and expired tokens are rejected with Unauthenticated,
+// and the failure of authserver is reported as Unavailable.
+func (a *TokenAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	token, err := BearerToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	user, valid, err := a.validator.Validate(ctx, token)
+	if err != nil {
+		a.logger.Error("failed to validate token", zap.Error(err))
+		return "", status.Error(codes.Unavailable, "authserver is temporarily unavailable. please try again later.")
+	}
+	if !valid {
+		if exp, ok := tokenExpiration(token); ok && !time.Now().Before(exp) {
+			return "", unauthenticated(pb.AuthenticationFailure_EXPIRED_TOKEN, "token has expired")
+		}
+		return "", unauthenticated(pb.AuthenticationFailure_INVALID_TOKEN, "invalid token")
+	}
+	return user, nil
+}
+
+// NoneAuthenticator accepts all requests without credential.
+// The bearer token is used as the user name if given, so that clients can identify themselves.
+// Since any client can claim any user, this must be used only in trusted networks,
+// and must not be combined with privileges granted by user name like admins.
+type NoneAuthenticator struct {
+	defaultUser string
+}
+
+// NewNoneAuthenticator returns new NoneAuthenticator.
+// defaultUser is used for the requests without bearer token.
+func NewNoneAuthenticator(defaultUser string) *NoneAuthenticator {
+	return &NoneAuthenticator{
+		defaultUser: defaultUser,
+	}
+}
+
+// Authenticate implements Authenticator.
+func (a *NoneAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	if user, err := BearerToken(ctx); err == nil {
+		return user, nil
+	}
+	return a.defaultUser, nil
+}
+
+// BearerToken returns the bearer token in the metadata of incoming request.
+// Returned error is already converted to gRPC status.
+func BearerToken(ctx context.Context) (string, error) {
+	token, err := grpc_auth.AuthFromMD(ctx, "bearer")
+	if err != nil || token == "" {
+		return "", unauthenticated(pb.AuthenticationFailure_MISSING_TOKEN, "bearer token is required")
+	}
+	return token, nil
+}
+
+// unauthenticated returns Unauthenticated status with reason in its details.
+func unauthenticated(reason pb.AuthenticationFailure_Reason, msg string) error {
+	st := status.New(codes.Unauthenticated, msg)
+	detailed, err := st.WithDetails(&pb.AuthenticationFailure{Reason: reason})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// bearerContext returns the context of incoming request which has token as bearer token.
+func bearerContext(token string) context.Context {
+	md := metadata.Pairs("authorization", "bearer "+token)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// failureReason returns the reason attached to Unauthenticated error.
+func failureReason(t *testing.T, err error) pb.AuthenticationFailure_Reason {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != codes.Unauthenticated {
+		t.Fatalf("code = %v, want Unauthenticated", st.Code())
+	}
+	for _, detail := range st.Details() {
+		if failure, ok := detail.(*pb.AuthenticationFailure); ok {
+			return failure.Reason
+		}
+	}
+	t.Fatalf("error has no AuthenticationFailure: %v", err)
+	return 0
+}
+
+func TestNoneAuthenticator(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no metadata", context.Background(), "anonymous"},
+		{"no bearer token", metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user", "alice")), "anonymous"},
+		{"bearer token", bearerContext("alice"), "alice"},
+	}
+	a := NewNoneAuthenticator("anonymous")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := a.Authenticate(tt.ctx)
+			if err != nil || user != tt.want {
+				t.Errorf("Authenticate = (%q, %v), want (%q, nil)", user, err, tt.want)
+			}
+		})
+	}
+}
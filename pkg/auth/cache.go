@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenKey is the key of tokenCache.
+// Tokens are not kept in memory as is, so that they cannot be taken from memory dump.
+type tokenKey [sha256.Size]byte
+
+// cacheEntry is the validated token in tokenCache.
+type cacheEntry struct {
+	key     tokenKey
+	user    string
+	expires time.Time
+}
+
+// tokenCache is the LRU cache of validated tokens with TTL.
+type tokenCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[tokenKey]*list.Element
+	// lru is ordered from the most recently used entry
+	lru *list.List
+}
+
+func newTokenCache(ttl time.Duration, size int) *tokenCache {
+	return &tokenCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[tokenKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get returns the user of token if token is cached and not expired.
+func (c *tokenCache) get(token string, now time.Time) (string, bool) {
+	key := sha256.Sum256([]byte(token))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !now.Before(entry.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return "", false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.user, true
+}
+
+// put caches token validated for user.
+// The entry expires after TTL or at the expiration time of token, whichever comes first.
+func (c *tokenCache) put(token, user string, now time.Time) {
+	if c.ttl <= 0 || c.size <= 0 {
+		return
+	}
+	expires := now.Add(c.ttl)
+	if exp, ok := tokenExpiration(token); ok && exp.Before(expires) {
+		expires = exp
+	}
+	if !now.Before(expires) {
+		return
+	}
+
+	key := sha256.Sum256([]byte(token))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = &cacheEntry{key: key, user: user, expires: expires}
+		c.lru.MoveToFront(elem)
+		return
+	}
+	for c.lru.Len() >= c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, user: user, expires: expires})
+}
+
+// len returns the number of cached tokens including expired ones not evicted yet.
+func (c *tokenCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// tokenExpiration returns the exp claim of JWT token.
+// The signature is not verified, so the result must be used only to shorten the lifetime of cache.
+func tokenExpiration(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp *int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+	return time.Unix(*claims.Exp, 0), true
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// unsignedToken returns the token which has only exp claim. The signature is not valid.
+func unsignedToken(exp time.Time) string {
+	claims := fmt.Sprintf(`{"sub":"alice","exp":%d}`, exp.Unix())
+	return "e30." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2ln"
+}
+
+func TestTokenCacheTTL(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	c := newTokenCache(time.Minute, 10)
+	c.put("token", "alice", now)
+
+	if user, ok := c.get("token", now.Add(59*time.Second)); !ok || user != "alice" {
+		t.Errorf("get before TTL = (%q, %v), want (alice, true)", user, ok)
+	}
+	if _, ok := c.get("token", now.Add(time.Minute)); ok {
+		t.Error("token is returned after TTL")
+	}
+	if n := c.len(); n != 0 {
+		t.Errorf("expired entry is not evicted: len = %d", n)
+	}
+}
+
+func TestTokenCacheExpiration(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	c := newTokenCache(time.Hour, 10)
+
+	token := unsignedToken(now.Add(10 * time.Second))
+	c.put(token, "alice", now)
+	if _, ok := c.get(token, now.Add(9*time.Second)); !ok {
+		t.Error("token is not cached before exp")
+	}
+	if _, ok := c.get(token, now.Add(10*time.Second)); ok {
+		t.Error("token is returned after exp though TTL is longer")
+	}
+
+	expired := unsignedToken(now.Add(-time.Second))
+	c.put(expired, "alice", now)
+	if n := c.len(); n != 0 {
+		t.Errorf("expired token is cached: len = %d", n)
+	}
+
+	// exp later than TTL does not extend the lifetime
+	long := unsignedToken(now.Add(2 * time.Hour))
+	c.put(long, "alice", now)
+	if _, ok := c.get(long, now.Add(time.Hour)); ok {
+		t.Error("token is returned after TTL though exp is later")
+	}
+}
+
+func TestTokenCacheLRU(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	c := newTokenCache(time.Hour, 2)
+	c.put("a", "alice", now)
+	c.put("b", "bob", now)
+	// a becomes the most recently used
+	if _, ok := c.get("a", now); !ok {
+		t.Fatal("a is not cached")
+	}
+	c.put("c", "carol", now)
+
+	if n := c.len(); n != 2 {
+		t.Errorf("len = %d, want 2", n)
+	}
+	if _, ok := c.get("b", now); ok {
+		t.Error("least recently used token is not evicted")
+	}
+	for _, token := range []string{"a", "c"} {
+		if _, ok := c.get(token, now); !ok {
+			t.Errorf("%s is evicted", token)
+		}
+	}
+}
+
+func TestTokenCacheDisabled(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	for _, c := range []*tokenCache{newTokenCache(0, 10), newTokenCache(time.Hour, 0)} {
+		c.put("token", "alice", now)
+		if _, ok := c.get("token", now); ok {
+			t.Errorf("token is cached with ttl %v and size %d", c.ttl, c.size)
+		}
+	}
+}
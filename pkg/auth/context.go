@@ -0,0 +1,19 @@
+package auth
+
+import "context"
+
+// userKey is the context key of authenticated user.
+// The unexported type prevents collisions with the keys defined in other packages.
+type userKey struct{}
+
+// NewContext returns new context which carries authenticated user.
+func NewContext(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the authenticated user stored in ctx.
+// ok is false if the request is not authenticated.
+func UserFromContext(ctx context.Context) (user string, ok bool) {
+	user, ok = ctx.Value(userKey{}).(string)
+	return user, ok
+}
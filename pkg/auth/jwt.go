@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // register hash functions used by signature algorithms
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// audience is the orig_host of the tokens issued for wrapups.
+	audience = "wrapups"
+	// clockSkew is the allowed difference between the clocks of authserver and wuserver.
+	clockSkew = 30 * time.Second
+)
+
+var (
+	// errUnknownKey is returned when no key can verify the token.
+	// Keys may have been rotated, so the token may be still valid for authserver.
+	errUnknownKey = errors.New("no key matches token")
+	// errInvalidSignature is returned when the signature of the token does not match.
+	// Keys may have been rotated, so the token may be still valid for authserver.
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// unsupportedAlgorithmError is returned when the token is signed with the algorithm which cannot be verified with public keys.
+type unsupportedAlgorithmError struct {
+	alg string
+}
+
+func (e *unsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("unsupported signature algorithm \"%s\"", e.alg)
+}
+
+// jwtHeader is the JOSE header of JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims is the claims of JWT used by wrapups.
+type jwtClaims struct {
+	Subject   string      `json:"sub"`
+	User      string      `json:"user"`
+	Audience  interface{} `json:"aud"`
+	OrigHost  string      `json:"orig_host"`
+	Expires   *int64      `json:"exp"`
+	NotBefore *int64      `json:"nbf"`
+}
+
+// user returns the user which the token is issued for.
+func (c *jwtClaims) user() string {
+	if c.Subject != "" {
+		return c.Subject
+	}
+	return c.User
+}
+
+// hasAudience reports whether the token is issued for aud.
+// authserver sets orig_host of the token request as the audience.
+func (c *jwtClaims) hasAudience(aud string) bool {
+	if c.OrigHost == aud {
+		return true
+	}
+	switch a := c.Audience.(type) {
+	case string:
+		return a == aud
+	case []interface{}:
+		for _, v := range a {
+			if s, ok := v.(string); ok && s == aud {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifyJWT verifies the signature and claims of token with keys, and returns the user of token.
+func verifyJWT(token string, keys *KeySet, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed token")
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", errors.Wrap(err, "malformed token header")
+	}
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", errors.Wrap(err, "malformed token claims")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.Wrap(err, "malformed token signature")
+	}
+
+	hash, err := signatureHash(header.Alg)
+	if err != nil {
+		return "", err
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	digest := h.Sum(nil)
+
+	candidates := keys.lookup(header.Kid)
+	if len(candidates) == 0 {
+		return "", errUnknownKey
+	}
+	verified := false
+	for _, key := range candidates {
+		if verifySignature(header.Alg, hash, key, digest, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return "", errInvalidSignature
+	}
+
+	if claims.Expires == nil {
+		return "", errors.New("token has no expiration time")
+	}
+	if !now.Before(time.Unix(*claims.Expires, 0).Add(clockSkew)) {
+		return "", errors.New("token has expired")
+	}
+	if claims.NotBefore != nil && now.Add(clockSkew).Before(time.Unix(*claims.NotBefore, 0)) {
+		return "", errors.New("token is not valid yet")
+	}
+	if !claims.hasAudience(audience) {
+		return "", errors.Errorf("token is not issued for %s", audience)
+	}
+	if claims.user() == "" {
+		return "", errors.New("token has no user")
+	}
+	return claims.user(), nil
+}
+
+// decodeSegment decodes base64url encoded JSON segment of JWT.
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// signatureHash returns the hash function of signature algorithm alg.
+// Only asymmetric algorithms are supported because wuserver does not have the secret of authserver.
+func signatureHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "PS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "PS512", "ES512":
+		return crypto.SHA512, nil
+	}
+	return 0, &unsupportedAlgorithmError{alg: alg}
+}
+
+// verifySignature reports whether sig is the valid signature of digest by key.
+func verifySignature(alg string, hash crypto.Hash, key crypto.PublicKey, digest, sig []byte) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(k, hash, digest, sig) == nil
+		case "PS":
+			return rsa.VerifyPSS(k, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			return false
+		}
+		// ECDSA signature of JWT is the concatenation of fixed size r and s
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
@@ -0,0 +1,295 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testKeys is the key pairs used to sign tokens in tests.
+type testKeys struct {
+	rsa      *rsa.PrivateKey
+	ec       *ecdsa.PrivateKey
+	otherRSA *rsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testKeys{rsa: rsaKey, ec: ecKey, otherRSA: other}
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// signToken returns JWT signed with key by alg, which is one of RS256, ES256 and HS256.
+func signToken(t *testing.T, alg, kid string, key interface{}, claims map[string]interface{}) string {
+	t.Helper()
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch alg {
+	case "RS256":
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, key.(*ecdsa.PrivateKey), digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(sig[32-len(rb):32], rb)
+		copy(sig[64-len(sb):], sb)
+	case "HS256":
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	default:
+		t.Fatalf("unknown algorithm %s", alg)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// writeFile writes contents to the file in the temporary directory and returns its path.
+func writeFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func pemPublicKey(t *testing.T, key crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func ecJWKS(t *testing.T, kid string, key *ecdsa.PublicKey) string {
+	t.Helper()
+	set := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"kid": kid,
+			"use": "sig",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+			"y":   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+		}},
+	}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestVerifyJWT(t *testing.T) {
+	keys := newTestKeys(t)
+	dir, err := ioutil.TempDir("", "wrapups-jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pemKeys, err := LoadKeySet(writeFile(t, dir, "rsa.pem", pemPublicKey(t, &keys.rsa.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksKeys, err := LoadKeySet(writeFile(t, dir, "jwks.json", ecJWKS(t, "ec-1", &keys.ec.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1500000000, 0)
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub":       "alice",
+			"orig_host": "wrapups",
+			"exp":       now.Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		keys    *KeySet
+		token   string
+		user    string
+		wantErr error
+	}{
+		{
+			name:  "RS256 with PEM key",
+			keys:  pemKeys,
+			token: signToken(t, "RS256", "", keys.rsa, claims(nil)),
+			user:  "alice",
+		},
+		{
+			name:  "ES256 with JWKS key",
+			keys:  jwksKeys,
+			token: signToken(t, "ES256", "ec-1", keys.ec, claims(nil)),
+			user:  "alice",
+		},
+		{
+			name:  "user claim and aud array",
+			keys:  pemKeys,
+			token: signToken(t, "RS256", "", keys.rsa, claims(map[string]interface{}{"sub": nil, "orig_host": nil, "user": "bob", "aud": []string{"other", "wrapups"}})),
+			user:  "bob",
+		},
+		{
+			name:  "expired within clock skew",
+			keys:  pemKeys,
+			token: signToken(t, "RS256", "", keys.rsa, claims(map[string]interface{}{"exp": now.Add(-clockSkew / 2).Unix()})),
+			user:  "alice",
+		},
+		{
+			name:  "expired",
+			keys:  pemKeys,
+			token: signToken(t, "RS256", "", keys.rsa, claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+		},
+		{
+			name:  "no exp",
+			keys:  pemKeys,
+			token: signToken(t, "RS256", "", keys.rsa, claims(map[string]interface{}{"exp": nil})),
+		},
+		{
+			name:  "not valid yet",
+			keys:  pemKeys,
+			token: signToken(t, "RS256", "", keys.rsa, claims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+		},
+		{
+			name:  "wrong audience",
+			keys:  pemKeys,
+			token: signToken(t, "RS256", "", keys.rsa, claims(map[string]interface{}{"orig_host": "other"})),
+		},
+		{
+			name:    "wrong key",
+			keys:    pemKeys,
+			token:   signToken(t, "RS256", "", keys.otherRSA, claims(nil)),
+			wantErr: errInvalidSignature,
+		},
+		{
+			name:    "unknown kid",
+			keys:    jwksKeys,
+			token:   signToken(t, "ES256", "ec-2", keys.ec, claims(nil)),
+			wantErr: errUnknownKey,
+		},
+		{
+			name:    "RS256 token with only EC keys",
+			keys:    jwksKeys,
+			token:   signToken(t, "RS256", "", keys.rsa, claims(nil)),
+			wantErr: errUnknownKey,
+		},
+		{
+			name:  "HS256",
+			keys:  pemKeys,
+			token: signToken(t, "HS256", "", []byte("secret"), claims(nil)),
+		},
+		{
+			name:  "malformed",
+			keys:  pemKeys,
+			token: "not.a-token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := verifyJWT(tt.token, tt.keys, now)
+			if tt.user != "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if user != tt.user {
+					t.Errorf("user = %q, want %q", user, tt.user)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("token is accepted as %q", user)
+			}
+			if tt.wantErr != nil && err != tt.wantErr {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyJWTUnsupportedAlgorithm(t *testing.T) {
+	keys := newTestKeys(t)
+	dir, err := ioutil.TempDir("", "wrapups-jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keySet, err := LoadKeySet(writeFile(t, dir, "rsa.pem", pemPublicKey(t, &keys.rsa.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := signToken(t, "HS256", "", []byte("secret"), map[string]interface{}{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	_, err = verifyJWT(token, keySet, time.Now())
+	if _, ok := err.(*unsupportedAlgorithmError); !ok {
+		t.Errorf("err = %v, want unsupportedAlgorithmError", err)
+	}
+}
+
+func TestLoadKeySetSymmetricJWKS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wrapups-jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeFile(t, dir, "jwks.json", `{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`)
+	if _, err := LoadKeySet(path); err == nil {
+		t.Error("JWKS with only symmetric keys is accepted")
+	}
+}
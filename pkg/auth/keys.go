@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// KeySet is the set of public keys to verify tokens issued by authserver.
+// Only RSA and EC keys are supported, so authserver must sign tokens with RS*, PS* or ES* algorithm.
+// Tokens signed with a shared secret like HS256 cannot be verified by wuserver.
+type KeySet struct {
+	keys []publicKey
+}
+
+// publicKey is the public key with optional key ID.
+type publicKey struct {
+	kid string
+	key crypto.PublicKey
+}
+
+// LoadKeySet loads public keys from file.
+// The file is either JWKS (JSON Web Key Set) or PEM encoded public keys or certificates.
+func LoadKeySet(file string) (*KeySet, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read public key file")
+	}
+	var keys []publicKey
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		keys, err = parseJWKS(b)
+	} else {
+		keys, err = parsePEMKeys(b)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("no public key found in \"%s\"", file)
+	}
+	return &KeySet{keys: keys}, nil
+}
+
+// Len returns the number of keys.
+func (s *KeySet) Len() int {
+	return len(s.keys)
+}
+
+// KeyTypes returns the types of keys like "RSA" and "EC" without duplicates.
+func (s *KeySet) KeyTypes() []string {
+	seen := make(map[string]bool)
+	var types []string
+	for _, k := range s.keys {
+		var typ string
+		switch k.key.(type) {
+		case *rsa.PublicKey:
+			typ = "RSA"
+		case *ecdsa.PublicKey:
+			typ = "EC"
+		default:
+			typ = "unsupported"
+		}
+		if !seen[typ] {
+			seen[typ] = true
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+// lookup returns the keys which may verify the token signed with key kid.
+// If no key has kid, all keys are returned because PEM keys do not have key ID.
+func (s *KeySet) lookup(kid string) []crypto.PublicKey {
+	keys := make([]crypto.PublicKey, 0, len(s.keys))
+	if kid != "" {
+		for _, k := range s.keys {
+			if k.kid == kid {
+				keys = append(keys, k.key)
+			}
+		}
+		if len(keys) > 0 {
+			return keys
+		}
+	}
+	for _, k := range s.keys {
+		if k.kid == "" {
+			keys = append(keys, k.key)
+		}
+	}
+	return keys
+}
+
+// parsePEMKeys parses PEM encoded public keys and certificates.
+func parsePEMKeys(b []byte) ([]publicKey, error) {
+	var keys []publicKey
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return keys, nil
+		}
+		var key crypto.PublicKey
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", block.Type)
+		}
+		keys = append(keys, publicKey{key: key})
+	}
+}
+
+// jwk is the JSON Web Key of RSA or EC public key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS parses JSON Web Key Set.
+// Keys not for signature and of unsupported types are ignored.
+func parseJWKS(b []byte) ([]publicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JWKS")
+	}
+	keys := make([]publicKey, 0, len(set.Keys))
+	symmetric := 0
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		var err error
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaKey()
+		case "EC":
+			key, err = k.ecKey()
+		case "oct":
+			symmetric++
+			continue
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key \"%s\" in JWKS", k.Kid)
+		}
+		keys = append(keys, publicKey{kid: k.Kid, key: key})
+	}
+	if len(keys) == 0 && symmetric > 0 {
+		return nil, errors.New("JWKS has only symmetric keys. authserver must sign tokens with RSA or EC key to verify them locally")
+	}
+	return keys, nil
+}
+
+func (k *jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid modulus")
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid exponent")
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent is too large")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k *jwk) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, errors.Errorf("unsupported curve \"%s\"", k.Crv)
+	}
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid x coordinate")
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid y coordinate")
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// decodeBigInt decodes base64url encoded big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
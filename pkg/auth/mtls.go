@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// MTLSAuthenticator authenticates requests with TLS client certificate.
+// The common name of the certificate is used as the user.
+// The server must verify client certificates, e.g. with tls.RequireAndVerifyClientCert.
+type MTLSAuthenticator struct{}
+
+// NewMTLSAuthenticator returns new MTLSAuthenticator.
+func NewMTLSAuthenticator() *MTLSAuthenticator {
+	return &MTLSAuthenticator{}
+}
+
+// Authenticate implements Authenticator.
+func (a *MTLSAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", unauthenticated(pb.AuthenticationFailure_MISSING_TOKEN, "client certificate is required")
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	// only verified chains are trusted, because PeerCertificates are not verified without client CA
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", unauthenticated(pb.AuthenticationFailure_MISSING_TOKEN, "client certificate is required")
+	}
+	user := info.State.VerifiedChains[0][0].Subject.CommonName
+	if user == "" {
+		return "", unauthenticated(pb.AuthenticationFailure_INVALID_TOKEN, "client certificate has no common name")
+	}
+	return user, nil
+}
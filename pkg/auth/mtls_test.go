@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// nonTLSInfo is the credentials.AuthInfo of the connection without TLS.
+type nonTLSInfo struct{}
+
+func (nonTLSInfo) AuthType() string {
+	return "insecure"
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 12345}
+	peerContext := func(info credentials.AuthInfo) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: info})
+	}
+	cert := func(cn string) *x509.Certificate {
+		return &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		reason pb.AuthenticationFailure_Reason
+	}{
+		{
+			name:   "no peer",
+			ctx:    context.Background(),
+			reason: pb.AuthenticationFailure_MISSING_TOKEN,
+		},
+		{
+			name:   "non-TLS peer",
+			ctx:    peerContext(nonTLSInfo{}),
+			reason: pb.AuthenticationFailure_MISSING_TOKEN,
+		},
+		{
+			name:   "no client certificate",
+			ctx:    peerContext(credentials.TLSInfo{}),
+			reason: pb.AuthenticationFailure_MISSING_TOKEN,
+		},
+		{
+			name: "unverified certificate",
+			ctx: peerContext(credentials.TLSInfo{State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert("alice")},
+			}}),
+			reason: pb.AuthenticationFailure_MISSING_TOKEN,
+		},
+		{
+			name: "verified chain without common name",
+			ctx: peerContext(credentials.TLSInfo{State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{cert(""), cert("ca")}},
+			}}),
+			reason: pb.AuthenticationFailure_INVALID_TOKEN,
+		},
+		{
+			name: "verified chain with common name",
+			ctx: peerContext(credentials.TLSInfo{State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert("alice")},
+				VerifiedChains:   [][]*x509.Certificate{{cert("alice"), cert("ca")}},
+			}}),
+			want: "alice",
+		},
+	}
+	a := NewMTLSAuthenticator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := a.Authenticate(tt.ctx)
+			if tt.want != "" {
+				if err != nil || user != tt.want {
+					t.Errorf("Authenticate = (%q, %v), want (%q, nil)", user, err, tt.want)
+				}
+				return
+			}
+			if reason := failureReason(t, err); reason != tt.reason {
+				t.Errorf("reason = %v, want %v", reason, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/mas9612/authserver/pkg/authserver"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// Validator validates tokens with authserver, or verifies them locally with the public keys of authserver.
+// Validated tokens are cached, so authserver is not called for every request.
+// Validator is safe for concurrent use.
+type Validator struct {
+	// counters are accessed atomically, so they are placed first to be 64-bit aligned
+	hits   uint64
+	misses uint64
+
+	// conn and client are nil if tokens are verified only locally
+	conn   *grpc.ClientConn
+	client pb.AuthserverClient
+	cache  *tokenCache
+	keys   *KeySet
+	logger *zap.Logger
+	// unsupportedAlgLogged is set to 1 after unsupported signature algorithm is logged.
+	unsupportedAlgLogged uint32
+}
+
+// validatorConfig is the configuration of Validator.
+type validatorConfig struct {
+	cacheTTL       time.Duration
+	cacheSize      int
+	keys           *KeySet
+	remoteFallback bool
+	logger         *zap.Logger
+}
+
+// ValidatorOption is the option of Validator.
+type ValidatorOption func(c *validatorConfig)
+
+// SetCacheTTL sets how long validated tokens are cached.
+// Tokens are not cached beyond their expiration time regardless of ttl.
+// If ttl is 0, tokens are not cached.
+// Default is 5 minutes.
+func SetCacheTTL(ttl time.Duration) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.cacheTTL = ttl
+	}
+}
+
+// SetCacheSize sets the maximum number of cached tokens.
+// If the cache is full, least recently used token is evicted.
+// Default is 10000.
+func SetCacheSize(size int) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.cacheSize = size
+	}
+}
+
+// SetLogger sets the logger of Validator. Default is no-op logger.
+func SetLogger(logger *zap.Logger) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.logger = logger
+	}
+}
+
+// SetKeySet sets the public keys to verify tokens locally without calling authserver.
+// The signature, exp, nbf and the audience of the token, which must be issued for wrapups, are verified.
+// authserver must sign tokens with RSA or EC key. If it signs with HMAC like HS256,
+// no token can be verified locally, and an error is logged once when such a token is received.
+func SetKeySet(keys *KeySet) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.keys = keys
+	}
+}
+
+// SetRemoteFallback sets whether the token which cannot be verified with the key set is validated by authserver.
+// The fallback is used only when no key matches the signature of the token, e.g. the keys have been rotated,
+// or the token is signed with unsupported algorithm.
+// This option is ignored if the key set is not set.
+func SetRemoteFallback(enabled bool) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.remoteFallback = enabled
+	}
+}
+
+// NewValidator creates new Validator connected to authserver.
+// The connection is established in background and kept until Close is called.
+// If the key set is set without remote fallback, authserver is not connected.
+//
+// Argument url is the url of authserver. It must be included both address and port number like localhost:10000.
+func NewValidator(url string, opts ...ValidatorOption) (*Validator, error) {
+	c := &validatorConfig{
+		cacheTTL:  5 * time.Minute,
+		cacheSize: 10000,
+		logger:    zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	v := &Validator{
+		cache:  newTokenCache(c.cacheTTL, c.cacheSize),
+		keys:   c.keys,
+		logger: c.logger,
+	}
+	if c.keys != nil && !c.remoteFallback {
+		return v, nil
+	}
+	conn, err := grpc.Dial(url, grpc.WithInsecure())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gRPC client")
+	}
+	v.conn = conn
+	v.client = pb.NewAuthserverClient(conn)
+	return v, nil
+}
+
+// Validate returns the user of token and whether token is valid.
+// The error is returned only when token cannot be validated, e.g. authserver is unavailable.
+func (v *Validator) Validate(ctx context.Context, token string) (string, bool, error) {
+	if user, ok := v.cache.get(token, time.Now()); ok {
+		atomic.AddUint64(&v.hits, 1)
+		return user, true, nil
+	}
+	atomic.AddUint64(&v.misses, 1)
+
+	if v.keys != nil {
+		user, err := verifyJWT(token, v.keys, time.Now())
+		if err == nil {
+			v.cache.put(token, user, time.Now())
+			return user, true, nil
+		}
+		_, unsupported := err.(*unsupportedAlgorithmError)
+		if unsupported && atomic.CompareAndSwapUint32(&v.unsupportedAlgLogged, 0, 1) {
+			v.logger.Error("token is signed with the algorithm which cannot be verified with public keys. "+
+				"authserver must sign tokens with RSA or EC key to verify them locally", zap.Error(err))
+		}
+		if v.client == nil || (err != errUnknownKey && err != errInvalidSignature && !unsupported) {
+			return "", false, nil
+		}
+	}
+
+	req := &pb.ValidateTokenRequest{
+		Token: token,
+	}
+	res, err := v.client.ValidateToken(ctx, req)
+	if err != nil {
+		return "", false, errors.Wrap(err, "failed to validate token")
+	}
+	if !res.Valid {
+		// invalid tokens are not cached, so that a flood of them does not evict valid ones
+		return "", false, nil
+	}
+	v.cache.put(token, res.User, time.Now())
+	return res.User, true, nil
+}
+
+// CacheStats is the statistics of token cache.
+type CacheStats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+	Entries int     `json:"entries"`
+}
+
+// Stats returns the statistics of token cache since Validator is created.
+func (v *Validator) Stats() CacheStats {
+	stats := CacheStats{
+		Hits:    atomic.LoadUint64(&v.hits),
+		Misses:  atomic.LoadUint64(&v.misses),
+		Entries: v.cache.len(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// Close closes the connection to authserver.
+func (v *Validator) Close() error {
+	if v.conn == nil {
+		return nil
+	}
+	return v.conn.Close()
+}
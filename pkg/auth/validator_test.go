@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/mas9612/authserver/pkg/authserver"
+	"google.golang.org/grpc"
+)
+
+// fakeAuthserver accepts the tokens in valid and counts ValidateToken calls.
+type fakeAuthserver struct {
+	mu    sync.Mutex
+	valid map[string]string
+	calls int
+}
+
+func (s *fakeAuthserver) CreateToken(ctx context.Context, req *pb.CreateTokenRequest) (*pb.Token, error) {
+	return &pb.Token{}, nil
+}
+
+func (s *fakeAuthserver) ValidateToken(ctx context.Context, req *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	user, ok := s.valid[req.Token]
+	return &pb.ValidateTokenResponse{Valid: ok, User: user}, nil
+}
+
+func (s *fakeAuthserver) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// startFakeAuthserver starts fake authserver and returns its address and a function to stop it.
+func startFakeAuthserver(t *testing.T, fake *fakeAuthserver) (string, func()) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterAuthserverServer(server, fake)
+	go server.Serve(listener)
+	return listener.Addr().String(), server.Stop
+}
+
+func TestValidatorCachesValidTokens(t *testing.T) {
+	token := unsignedToken(time.Now().Add(time.Hour))
+	fake := &fakeAuthserver{valid: map[string]string{token: "alice"}}
+	addr, stop := startFakeAuthserver(t, fake)
+	defer stop()
+
+	v, err := NewValidator(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		user, valid, err := v.Validate(ctx, token)
+		if err != nil || !valid || user != "alice" {
+			t.Fatalf("Validate = (%q, %v, %v), want (alice, true, nil)", user, valid, err)
+		}
+	}
+	if n := fake.callCount(); n != 1 {
+		t.Errorf("authserver is called %d times, want 1", n)
+	}
+	stats := v.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, want 2 hits, 1 miss and 1 entry", stats)
+	}
+}
+
+func TestValidatorDoesNotCacheInvalidTokens(t *testing.T) {
+	fake := &fakeAuthserver{valid: map[string]string{}}
+	addr, stop := startFakeAuthserver(t, fake)
+	defer stop()
+
+	v, err := NewValidator(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token := unsignedToken(time.Now().Add(time.Hour))
+	for i := 0; i < 2; i++ {
+		if _, valid, err := v.Validate(ctx, token); err != nil || valid {
+			t.Fatalf("Validate = (%v, %v), want (false, nil)", valid, err)
+		}
+	}
+	if n := fake.callCount(); n != 2 {
+		t.Errorf("authserver is called %d times, want 2", n)
+	}
+	if stats := v.Stats(); stats.Hits != 0 || stats.Entries != 0 {
+		t.Errorf("invalid token is cached: %+v", stats)
+	}
+}
+
+func TestValidatorDoesNotCacheFailures(t *testing.T) {
+	// nothing listens on this address
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	v, err := NewValidator(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, _, err := v.Validate(ctx, unsignedToken(time.Now().Add(time.Hour))); err == nil {
+		t.Fatal("error is not returned while authserver is down")
+	}
+	if n := v.Stats().Entries; n != 0 {
+		t.Errorf("token is cached after failure: %d entries", n)
+	}
+}
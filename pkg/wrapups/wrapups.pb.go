@@ -8,6 +8,7 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
 	grpc "google.golang.org/grpc"
 	math "math"
 )
@@ -23,6 +24,90 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+//*
+// Step represents the step of reindex.
+type ReindexProgress_Step int32
+
+const (
+	ReindexProgress_STEP_UNSPECIFIED ReindexProgress_Step = 0
+	// creating new index.
+	ReindexProgress_CREATE_INDEX ReindexProgress_Step = 1
+	// copying documents to new index.
+	ReindexProgress_COPY ReindexProgress_Step = 2
+	// verifying the number of documents in new index.
+	ReindexProgress_VERIFY ReindexProgress_Step = 3
+	// switching the alias to new index.
+	ReindexProgress_SWITCH_ALIAS ReindexProgress_Step = 4
+	// deleting old index.
+	ReindexProgress_DELETE_OLD_INDEX ReindexProgress_Step = 5
+	// reindex finished.
+	ReindexProgress_DONE ReindexProgress_Step = 6
+)
+
+var ReindexProgress_Step_name = map[int32]string{
+	0: "STEP_UNSPECIFIED",
+	1: "CREATE_INDEX",
+	2: "COPY",
+	3: "VERIFY",
+	4: "SWITCH_ALIAS",
+	5: "DELETE_OLD_INDEX",
+	6: "DONE",
+}
+
+var ReindexProgress_Step_value = map[string]int32{
+	"STEP_UNSPECIFIED": 0,
+	"CREATE_INDEX":     1,
+	"COPY":             2,
+	"VERIFY":           3,
+	"SWITCH_ALIAS":     4,
+	"DELETE_OLD_INDEX": 5,
+	"DONE":             6,
+}
+
+func (x ReindexProgress_Step) String() string {
+	return proto.EnumName(ReindexProgress_Step_name, int32(x))
+}
+
+func (ReindexProgress_Step) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{37, 0}
+}
+
+//*
+// Reason represents why the request is not authenticated.
+type AuthenticationFailure_Reason int32
+
+const (
+	AuthenticationFailure_REASON_UNSPECIFIED AuthenticationFailure_Reason = 0
+	// bearer token or client certificate is not given.
+	AuthenticationFailure_MISSING_TOKEN AuthenticationFailure_Reason = 1
+	// token, API key or client certificate is invalid.
+	AuthenticationFailure_INVALID_TOKEN AuthenticationFailure_Reason = 2
+	// token has expired.
+	AuthenticationFailure_EXPIRED_TOKEN AuthenticationFailure_Reason = 3
+)
+
+var AuthenticationFailure_Reason_name = map[int32]string{
+	0: "REASON_UNSPECIFIED",
+	1: "MISSING_TOKEN",
+	2: "INVALID_TOKEN",
+	3: "EXPIRED_TOKEN",
+}
+
+var AuthenticationFailure_Reason_value = map[string]int32{
+	"REASON_UNSPECIFIED": 0,
+	"MISSING_TOKEN":      1,
+	"INVALID_TOKEN":      2,
+	"EXPIRED_TOKEN":      3,
+}
+
+func (x AuthenticationFailure_Reason) String() string {
+	return proto.EnumName(AuthenticationFailure_Reason_name, int32(x))
+}
+
+func (AuthenticationFailure_Reason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{38, 0}
+}
+
 //*
 // Wrapup represents one wrapup object.
 type Wrapup struct {
@@ -37,10 +122,26 @@ type Wrapup struct {
 	// notes of the paper.
 	Note string `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
 	// timestamp which indicates when this wrapup object is created.
-	CreateTime           *timestamp.Timestamp `protobuf:"bytes,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	CreateTime *timestamp.Timestamp `protobuf:"bytes,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// timestamp which indicates when this wrapup object is updated last time.
+	UpdateTime *timestamp.Timestamp `protobuf:"bytes,7,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// timestamp which indicates when this wrapup object is moved to the trash.
+	// this field is set only when the wrapup object is deleted.
+	DeleteTime *timestamp.Timestamp `protobuf:"bytes,8,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
+	// bibliographic metadata of the paper.
+	Metadata *PaperMetadata `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// tags to categorize the wrapup object. e.g. "networking", "ML-systems"
+	Tags []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	// user who created this wrapup object. set by server.
+	CreatedBy string `protobuf:"bytes,11,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	// user who updated this wrapup object last time. set by server.
+	UpdatedBy string `protobuf:"bytes,12,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	// version of this wrapup object, which is incremented every time it is changed. set by server.
+	// if set in UpdateWrapupRequest, the update fails with ABORTED unless it is the current version.
+	Version              int64    `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Wrapup) Reset()         { *m = Wrapup{} }
@@ -110,11 +211,180 @@ func (m *Wrapup) GetCreateTime() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *Wrapup) GetUpdateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdateTime
+	}
+	return nil
+}
+
+func (m *Wrapup) GetDeleteTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeleteTime
+	}
+	return nil
+}
+
+func (m *Wrapup) GetMetadata() *PaperMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Wrapup) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *Wrapup) GetCreatedBy() string {
+	if m != nil {
+		return m.CreatedBy
+	}
+	return ""
+}
+
+func (m *Wrapup) GetUpdatedBy() string {
+	if m != nil {
+		return m.UpdatedBy
+	}
+	return ""
+}
+
+func (m *Wrapup) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+//*
+// PaperMetadata represents the bibliographic metadata of a paper.
+type PaperMetadata struct {
+	// list of author names.
+	Authors []string `protobuf:"bytes,1,rep,name=authors,proto3" json:"authors,omitempty"`
+	// conference or journal name in which the paper is published.
+	Venue string `protobuf:"bytes,2,opt,name=venue,proto3" json:"venue,omitempty"`
+	// publication year. it must be between 1000 and next year.
+	Year int32 `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	// DOI of the paper. e.g. 10.1145/3190508.3190510
+	Doi string `protobuf:"bytes,4,opt,name=doi,proto3" json:"doi,omitempty"`
+	// arXiv identifier of the paper without "arXiv:" prefix. e.g. 1706.03762, hep-th/9901001
+	ArxivId string `protobuf:"bytes,5,opt,name=arxiv_id,json=arxivId,proto3" json:"arxiv_id,omitempty"`
+	// URL of the paper.
+	Url string `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	// abstract of the paper.
+	Abstract             string   `protobuf:"bytes,7,opt,name=abstract,proto3" json:"abstract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PaperMetadata) Reset()         { *m = PaperMetadata{} }
+func (m *PaperMetadata) String() string { return proto.CompactTextString(m) }
+func (*PaperMetadata) ProtoMessage()    {}
+func (*PaperMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{1}
+}
+
+func (m *PaperMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PaperMetadata.Unmarshal(m, b)
+}
+func (m *PaperMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PaperMetadata.Marshal(b, m, deterministic)
+}
+func (m *PaperMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PaperMetadata.Merge(m, src)
+}
+func (m *PaperMetadata) XXX_Size() int {
+	return xxx_messageInfo_PaperMetadata.Size(m)
+}
+func (m *PaperMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_PaperMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PaperMetadata proto.InternalMessageInfo
+
+func (m *PaperMetadata) GetAuthors() []string {
+	if m != nil {
+		return m.Authors
+	}
+	return nil
+}
+
+func (m *PaperMetadata) GetVenue() string {
+	if m != nil {
+		return m.Venue
+	}
+	return ""
+}
+
+func (m *PaperMetadata) GetYear() int32 {
+	if m != nil {
+		return m.Year
+	}
+	return 0
+}
+
+func (m *PaperMetadata) GetDoi() string {
+	if m != nil {
+		return m.Doi
+	}
+	return ""
+}
+
+func (m *PaperMetadata) GetArxivId() string {
+	if m != nil {
+		return m.ArxivId
+	}
+	return ""
+}
+
+func (m *PaperMetadata) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *PaperMetadata) GetAbstract() string {
+	if m != nil {
+		return m.Abstract
+	}
+	return ""
+}
+
 //*
 // ListWrapupsRequest represents the request message for List operation.
 type ListWrapupsRequest struct {
 	// filter is used to filter wrapup document to return only matched ones.
-	Filter               string   `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// filter is an expression combining comparisons with AND, OR, NOT and parentheses.
+	// e.g. title:attention AND create_time>=2019-01-01 AND NOT note:draft.
+	// a value without field name matches the wrapup field.
+	Filter string `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// if set, only wrapup documents written by this author are returned.
+	Author string `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	// if set, only wrapup documents published in this venue are returned.
+	Venue string `protobuf:"bytes,3,opt,name=venue,proto3" json:"venue,omitempty"`
+	// if set, only wrapup documents published in this year are returned.
+	Year int32 `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
+	// if set, only wrapup documents which have all of these tags are returned.
+	Tags []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// maximum number of wrapup objects to return in one response.
+	// if not set, server default (50) is used. values above 1000 are coerced to 1000.
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token returned by the previous List operation.
+	// if not set, the first page is returned.
+	PageToken string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// order_by is comma-separated list of fields to sort results, each optionally followed by "asc" or "desc".
+	// supported fields are create_time, title and relevance. e.g. "create_time desc"
+	// if not set, results are sorted by "relevance desc, create_time desc".
+	OrderBy string `protobuf:"bytes,8,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// if set, only wrapup documents created by this user are returned.
+	// "mine" means the user who sends the request.
+	CreatedBy            string   `protobuf:"bytes,9,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -124,7 +394,7 @@ func (m *ListWrapupsRequest) Reset()         { *m = ListWrapupsRequest{} }
 func (m *ListWrapupsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListWrapupsRequest) ProtoMessage()    {}
 func (*ListWrapupsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_685ed5c71b2573e9, []int{1}
+	return fileDescriptor_685ed5c71b2573e9, []int{2}
 }
 
 func (m *ListWrapupsRequest) XXX_Unmarshal(b []byte) error {
@@ -152,23 +422,85 @@ func (m *ListWrapupsRequest) GetFilter() string {
 	return ""
 }
 
+func (m *ListWrapupsRequest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *ListWrapupsRequest) GetVenue() string {
+	if m != nil {
+		return m.Venue
+	}
+	return ""
+}
+
+func (m *ListWrapupsRequest) GetYear() int32 {
+	if m != nil {
+		return m.Year
+	}
+	return 0
+}
+
+func (m *ListWrapupsRequest) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *ListWrapupsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListWrapupsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListWrapupsRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
+func (m *ListWrapupsRequest) GetCreatedBy() string {
+	if m != nil {
+		return m.CreatedBy
+	}
+	return ""
+}
+
 //*
 // ListWrapupsResponse represents the response of List operation.
 type ListWrapupsResponse struct {
-	// number of wrapup objects included in this response.
+	// total number of wrapup objects matched to the request, which is the same as total_size.
+	// kept for compatibility. use total_size instead.
 	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
 	// list of wrapup object.
-	Wrapups              []*Wrapup `protobuf:"bytes,2,rep,name=wrapups,proto3" json:"wrapups,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	Wrapups []*Wrapup `protobuf:"bytes,2,rep,name=wrapups,proto3" json:"wrapups,omitempty"`
+	// token to retrieve the next page of results.
+	// empty if there are no more results.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// total number of wrapup objects matched to the request across all pages.
+	TotalSize            int32    `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ListWrapupsResponse) Reset()         { *m = ListWrapupsResponse{} }
 func (m *ListWrapupsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListWrapupsResponse) ProtoMessage()    {}
 func (*ListWrapupsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_685ed5c71b2573e9, []int{2}
+	return fileDescriptor_685ed5c71b2573e9, []int{3}
 }
 
 func (m *ListWrapupsResponse) XXX_Unmarshal(b []byte) error {
@@ -203,6 +535,20 @@ func (m *ListWrapupsResponse) GetWrapups() []*Wrapup {
 	return nil
 }
 
+func (m *ListWrapupsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListWrapupsResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
 //*
 // GetWrapupRequest represents the request message for Get operation.
 type GetWrapupRequest struct {
@@ -217,7 +563,7 @@ func (m *GetWrapupRequest) Reset()         { *m = GetWrapupRequest{} }
 func (m *GetWrapupRequest) String() string { return proto.CompactTextString(m) }
 func (*GetWrapupRequest) ProtoMessage()    {}
 func (*GetWrapupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_685ed5c71b2573e9, []int{3}
+	return fileDescriptor_685ed5c71b2573e9, []int{4}
 }
 
 func (m *GetWrapupRequest) XXX_Unmarshal(b []byte) error {
@@ -255,7 +601,13 @@ type CreateWrapupRequest struct {
 	// comment of paper.
 	Comment string `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
 	// note of paper.
-	Note                 string   `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	Note string `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	// bibliographic metadata of paper.
+	Metadata *PaperMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// tags of paper.
+	Tags []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// if true, create the wrapup object even if the one with the same title already exists.
+	AllowDuplicate       bool     `protobuf:"varint,7,opt,name=allow_duplicate,json=allowDuplicate,proto3" json:"allow_duplicate,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -265,7 +617,7 @@ func (m *CreateWrapupRequest) Reset()         { *m = CreateWrapupRequest{} }
 func (m *CreateWrapupRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateWrapupRequest) ProtoMessage()    {}
 func (*CreateWrapupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_685ed5c71b2573e9, []int{4}
+	return fileDescriptor_685ed5c71b2573e9, []int{5}
 }
 
 func (m *CreateWrapupRequest) XXX_Unmarshal(b []byte) error {
@@ -314,162 +666,2548 @@ func (m *CreateWrapupRequest) GetNote() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*Wrapup)(nil), "wrapups.Wrapup")
-	proto.RegisterType((*ListWrapupsRequest)(nil), "wrapups.ListWrapupsRequest")
-	proto.RegisterType((*ListWrapupsResponse)(nil), "wrapups.ListWrapupsResponse")
-	proto.RegisterType((*GetWrapupRequest)(nil), "wrapups.GetWrapupRequest")
-	proto.RegisterType((*CreateWrapupRequest)(nil), "wrapups.CreateWrapupRequest")
+func (m *CreateWrapupRequest) GetMetadata() *PaperMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("pkg/wrapups/wrapups.proto", fileDescriptor_685ed5c71b2573e9) }
-
-var fileDescriptor_685ed5c71b2573e9 = []byte{
-	// 355 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xcd, 0x4a, 0xc3, 0x40,
-	0x14, 0x85, 0x9b, 0xb4, 0x4d, 0xe9, 0x8d, 0xa8, 0xdc, 0x8a, 0x4c, 0xa3, 0x60, 0x99, 0x55, 0x05,
-	0x49, 0xa1, 0xae, 0xc4, 0x85, 0x0b, 0x17, 0x82, 0xb8, 0x0a, 0xa2, 0x4b, 0xe9, 0xcf, 0xb4, 0x04,
-	0x9b, 0x4c, 0x9a, 0x99, 0xe0, 0x73, 0xf9, 0x28, 0xbe, 0x91, 0x64, 0x7e, 0xd2, 0x36, 0xc6, 0x55,
-	0x72, 0xee, 0x3d, 0xdc, 0x9c, 0xf3, 0x11, 0x18, 0x66, 0x9f, 0xeb, 0xc9, 0x57, 0x3e, 0xcb, 0x8a,
-	0x4c, 0xd8, 0x67, 0x98, 0xe5, 0x5c, 0x72, 0xec, 0x19, 0x19, 0x5c, 0xad, 0x39, 0x5f, 0x6f, 0xd8,
-	0x44, 0x8d, 0xe7, 0xc5, 0x6a, 0x22, 0xe3, 0x84, 0x09, 0x39, 0x4b, 0x32, 0xed, 0xa4, 0xdf, 0x0e,
-	0x78, 0xef, 0xca, 0x8c, 0xc7, 0xe0, 0xc6, 0x4b, 0xe2, 0x8c, 0x9c, 0x71, 0x3f, 0x72, 0xe3, 0x25,
-	0x9e, 0x41, 0x57, 0xc6, 0x72, 0xc3, 0x88, 0xab, 0x46, 0x5a, 0xe0, 0x39, 0x78, 0xfa, 0x38, 0x69,
-	0xab, 0xb1, 0x51, 0x48, 0xa0, 0xb7, 0xe0, 0x49, 0xc2, 0x52, 0x49, 0x3a, 0x6a, 0x61, 0x25, 0x22,
-	0x74, 0x52, 0x2e, 0x19, 0xe9, 0xaa, 0xb1, 0x7a, 0xc7, 0x7b, 0xf0, 0x17, 0x39, 0x9b, 0x49, 0xf6,
-	0x51, 0x06, 0x22, 0xde, 0xc8, 0x19, 0xfb, 0xd3, 0x20, 0xd4, 0x69, 0x43, 0x9b, 0x36, 0x7c, 0xb5,
-	0x69, 0x23, 0xd0, 0xf6, 0x72, 0x40, 0x6f, 0x00, 0x5f, 0x62, 0x21, 0x75, 0x6c, 0x11, 0xb1, 0x6d,
-	0xc1, 0x84, 0x2c, 0x83, 0xad, 0xe2, 0x8d, 0x64, 0xb9, 0xa9, 0x60, 0x14, 0x7d, 0x83, 0xc1, 0x81,
-	0x5b, 0x64, 0x3c, 0x15, 0xac, 0x6c, 0xb7, 0xe0, 0x45, 0x2a, 0x95, 0xbb, 0x1b, 0x69, 0x81, 0xd7,
-	0x60, 0xd1, 0x11, 0x77, 0xd4, 0x1e, 0xfb, 0xd3, 0x93, 0xd0, 0x92, 0xd5, 0x07, 0x22, 0xbb, 0xa7,
-	0x14, 0x4e, 0x9f, 0x98, 0x39, 0x6b, 0x33, 0xd4, 0x10, 0xd2, 0x2d, 0x0c, 0x1e, 0x55, 0xee, 0x43,
-	0x5b, 0x45, 0xd6, 0x69, 0x26, 0xeb, 0xfe, 0x47, 0xb6, 0xdd, 0x4c, 0xb6, 0xb3, 0x23, 0x3b, 0xfd,
-	0x71, 0xa0, 0x67, 0xba, 0xe2, 0x33, 0xf8, 0x7b, 0xd5, 0xf1, 0xa2, 0xea, 0xf2, 0x17, 0x5f, 0x70,
-	0xd9, 0xbc, 0xd4, 0xb4, 0x68, 0x0b, 0xef, 0xa0, 0x5f, 0xd5, 0xc5, 0x61, 0x65, 0xae, 0x23, 0x08,
-	0xea, 0xc0, 0x68, 0x0b, 0x1f, 0xe0, 0x68, 0x9f, 0x02, 0xee, 0x3e, 0xd5, 0x00, 0xa7, 0xe1, 0xc0,
-	0xdc, 0x53, 0x3f, 0xc4, 0xed, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x99, 0x07, 0xa1, 0x1d, 0xf2,
-	0x02, 0x00, 0x00,
+func (m *CreateWrapupRequest) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *CreateWrapupRequest) GetAllowDuplicate() bool {
+	if m != nil {
+		return m.AllowDuplicate
+	}
+	return false
+}
 
-// WrapupsClient is the client API for Wrapups service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type WrapupsClient interface {
-	// ListWrapups returns the list of wrapup document stored in Elasticsearch.
-	ListWrapups(ctx context.Context, in *ListWrapupsRequest, opts ...grpc.CallOption) (*ListWrapupsResponse, error)
-	// GetWrapup returns a wrapup document matched to request.
-	GetWrapup(ctx context.Context, in *GetWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
-	// CreateWrapup creates new wrapup document and stores it in Elasticsearch.
-	CreateWrapup(ctx context.Context, in *CreateWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
+//*
+// DuplicateTitle is attached to the details of AlreadyExists error returned by Create operation.
+type DuplicateTitle struct {
+	// ID of the existing wrapup object which has the same title.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// title of the existing wrapup object.
+	Title                string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type wrapupsClient struct {
-	cc *grpc.ClientConn
+func (m *DuplicateTitle) Reset()         { *m = DuplicateTitle{} }
+func (m *DuplicateTitle) String() string { return proto.CompactTextString(m) }
+func (*DuplicateTitle) ProtoMessage()    {}
+func (*DuplicateTitle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{6}
 }
 
-func NewWrapupsClient(cc *grpc.ClientConn) WrapupsClient {
-	return &wrapupsClient{cc}
+func (m *DuplicateTitle) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DuplicateTitle.Unmarshal(m, b)
+}
+func (m *DuplicateTitle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DuplicateTitle.Marshal(b, m, deterministic)
+}
+func (m *DuplicateTitle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DuplicateTitle.Merge(m, src)
+}
+func (m *DuplicateTitle) XXX_Size() int {
+	return xxx_messageInfo_DuplicateTitle.Size(m)
+}
+func (m *DuplicateTitle) XXX_DiscardUnknown() {
+	xxx_messageInfo_DuplicateTitle.DiscardUnknown(m)
 }
 
-func (c *wrapupsClient) ListWrapups(ctx context.Context, in *ListWrapupsRequest, opts ...grpc.CallOption) (*ListWrapupsResponse, error) {
-	out := new(ListWrapupsResponse)
-	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/ListWrapups", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DuplicateTitle proto.InternalMessageInfo
+
+func (m *DuplicateTitle) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return ""
 }
 
-func (c *wrapupsClient) GetWrapup(ctx context.Context, in *GetWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
-	out := new(Wrapup)
-	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/GetWrapup", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *DuplicateTitle) GetTitle() string {
+	if m != nil {
+		return m.Title
 	}
-	return out, nil
+	return ""
 }
 
-func (c *wrapupsClient) CreateWrapup(ctx context.Context, in *CreateWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
-	out := new(Wrapup)
-	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/CreateWrapup", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+//*
+// UpdateWrapupRequest represents the request message for Update operation.
+type UpdateWrapupRequest struct {
+	// wrapup object to update. id is required.
+	// set version to the one read before to avoid overwriting concurrent updates.
+	Wrapup *Wrapup `protobuf:"bytes,1,opt,name=wrapup,proto3" json:"wrapup,omitempty"`
+	// fields of wrapup object to update.
+	// if not set, all updatable fields (title, wrapup, comment, note, metadata and tags) are replaced.
+	// each field of metadata can be specified separately like "metadata.authors".
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// if true, change the title even if the wrapup object with the new title already exists.
+	AllowDuplicate       bool     `protobuf:"varint,3,opt,name=allow_duplicate,json=allowDuplicate,proto3" json:"allow_duplicate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// WrapupsServer is the server API for Wrapups service.
-type WrapupsServer interface {
+func (m *UpdateWrapupRequest) Reset()         { *m = UpdateWrapupRequest{} }
+func (m *UpdateWrapupRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateWrapupRequest) ProtoMessage()    {}
+func (*UpdateWrapupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{7}
+}
+
+func (m *UpdateWrapupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateWrapupRequest.Unmarshal(m, b)
+}
+func (m *UpdateWrapupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateWrapupRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateWrapupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateWrapupRequest.Merge(m, src)
+}
+func (m *UpdateWrapupRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateWrapupRequest.Size(m)
+}
+func (m *UpdateWrapupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateWrapupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateWrapupRequest proto.InternalMessageInfo
+
+func (m *UpdateWrapupRequest) GetWrapup() *Wrapup {
+	if m != nil {
+		return m.Wrapup
+	}
+	return nil
+}
+
+func (m *UpdateWrapupRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UpdateWrapupRequest) GetAllowDuplicate() bool {
+	if m != nil {
+		return m.AllowDuplicate
+	}
+	return false
+}
+
+//*
+// DeleteWrapupRequest represents the request message for Delete operation.
+type DeleteWrapupRequest struct {
+	// id of wrapup object to delete.
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWrapupRequest) Reset()         { *m = DeleteWrapupRequest{} }
+func (m *DeleteWrapupRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWrapupRequest) ProtoMessage()    {}
+func (*DeleteWrapupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{8}
+}
+
+func (m *DeleteWrapupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWrapupRequest.Unmarshal(m, b)
+}
+func (m *DeleteWrapupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWrapupRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteWrapupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWrapupRequest.Merge(m, src)
+}
+func (m *DeleteWrapupRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWrapupRequest.Size(m)
+}
+func (m *DeleteWrapupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWrapupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWrapupRequest proto.InternalMessageInfo
+
+func (m *DeleteWrapupRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+//*
+// UndeleteWrapupRequest represents the request message for Undelete operation.
+type UndeleteWrapupRequest struct {
+	// id of wrapup object to restore from the trash.
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UndeleteWrapupRequest) Reset()         { *m = UndeleteWrapupRequest{} }
+func (m *UndeleteWrapupRequest) String() string { return proto.CompactTextString(m) }
+func (*UndeleteWrapupRequest) ProtoMessage()    {}
+func (*UndeleteWrapupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{9}
+}
+
+func (m *UndeleteWrapupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteWrapupRequest.Unmarshal(m, b)
+}
+func (m *UndeleteWrapupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteWrapupRequest.Marshal(b, m, deterministic)
+}
+func (m *UndeleteWrapupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteWrapupRequest.Merge(m, src)
+}
+func (m *UndeleteWrapupRequest) XXX_Size() int {
+	return xxx_messageInfo_UndeleteWrapupRequest.Size(m)
+}
+func (m *UndeleteWrapupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteWrapupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndeleteWrapupRequest proto.InternalMessageInfo
+
+func (m *UndeleteWrapupRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+//*
+// ListDeletedWrapupsRequest represents the request message for ListDeleted operation.
+type ListDeletedWrapupsRequest struct {
+	// filter is used to filter wrapup document to return only matched ones.
+	// the syntax is the same as ListWrapupsRequest.filter.
+	Filter string `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// maximum number of wrapup objects to return in one response.
+	// if not set, server default (50) is used. values above 1000 are coerced to 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token returned by the previous ListDeleted operation.
+	// if not set, the first page is returned.
+	PageToken            string   `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDeletedWrapupsRequest) Reset()         { *m = ListDeletedWrapupsRequest{} }
+func (m *ListDeletedWrapupsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDeletedWrapupsRequest) ProtoMessage()    {}
+func (*ListDeletedWrapupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{10}
+}
+
+func (m *ListDeletedWrapupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeletedWrapupsRequest.Unmarshal(m, b)
+}
+func (m *ListDeletedWrapupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeletedWrapupsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDeletedWrapupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeletedWrapupsRequest.Merge(m, src)
+}
+func (m *ListDeletedWrapupsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDeletedWrapupsRequest.Size(m)
+}
+func (m *ListDeletedWrapupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeletedWrapupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeletedWrapupsRequest proto.InternalMessageInfo
+
+func (m *ListDeletedWrapupsRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *ListDeletedWrapupsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListDeletedWrapupsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+//*
+// WrapupRevision represents one prior revision of wrapup object.
+type WrapupRevision struct {
+	// ID of the wrapup object which this revision belongs to.
+	WrapupId string `protobuf:"bytes,1,opt,name=wrapup_id,json=wrapupId,proto3" json:"wrapup_id,omitempty"`
+	// revision number, which is the version of the wrapup object having these contents.
+	// it increases as the wrapup object is changed, but skips the versions recorded by no revision like deletion.
+	Revision int32 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// contents of the wrapup object at this revision.
+	Wrapup *Wrapup `protobuf:"bytes,3,opt,name=wrapup,proto3" json:"wrapup,omitempty"`
+	// timestamp which indicates when this revision is replaced with newer contents.
+	RevisionCreateTime   *timestamp.Timestamp `protobuf:"bytes,4,opt,name=revision_create_time,json=revisionCreateTime,proto3" json:"revision_create_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WrapupRevision) Reset()         { *m = WrapupRevision{} }
+func (m *WrapupRevision) String() string { return proto.CompactTextString(m) }
+func (*WrapupRevision) ProtoMessage()    {}
+func (*WrapupRevision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{11}
+}
+
+func (m *WrapupRevision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WrapupRevision.Unmarshal(m, b)
+}
+func (m *WrapupRevision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WrapupRevision.Marshal(b, m, deterministic)
+}
+func (m *WrapupRevision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WrapupRevision.Merge(m, src)
+}
+func (m *WrapupRevision) XXX_Size() int {
+	return xxx_messageInfo_WrapupRevision.Size(m)
+}
+func (m *WrapupRevision) XXX_DiscardUnknown() {
+	xxx_messageInfo_WrapupRevision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WrapupRevision proto.InternalMessageInfo
+
+func (m *WrapupRevision) GetWrapupId() string {
+	if m != nil {
+		return m.WrapupId
+	}
+	return ""
+}
+
+func (m *WrapupRevision) GetRevision() int32 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *WrapupRevision) GetWrapup() *Wrapup {
+	if m != nil {
+		return m.Wrapup
+	}
+	return nil
+}
+
+func (m *WrapupRevision) GetRevisionCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.RevisionCreateTime
+	}
+	return nil
+}
+
+//*
+// ListWrapupRevisionsRequest represents the request message for ListRevisions operation.
+type ListWrapupRevisionsRequest struct {
+	// id of wrapup object.
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWrapupRevisionsRequest) Reset()         { *m = ListWrapupRevisionsRequest{} }
+func (m *ListWrapupRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWrapupRevisionsRequest) ProtoMessage()    {}
+func (*ListWrapupRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{12}
+}
+
+func (m *ListWrapupRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWrapupRevisionsRequest.Unmarshal(m, b)
+}
+func (m *ListWrapupRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWrapupRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWrapupRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWrapupRevisionsRequest.Merge(m, src)
+}
+func (m *ListWrapupRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWrapupRevisionsRequest.Size(m)
+}
+func (m *ListWrapupRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWrapupRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWrapupRevisionsRequest proto.InternalMessageInfo
+
+func (m *ListWrapupRevisionsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+//*
+// ListWrapupRevisionsResponse represents the response of ListRevisions operation.
+type ListWrapupRevisionsResponse struct {
+	// number of revisions included in this response.
+	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// list of revisions ordered from oldest to newest.
+	Revisions            []*WrapupRevision `protobuf:"bytes,2,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ListWrapupRevisionsResponse) Reset()         { *m = ListWrapupRevisionsResponse{} }
+func (m *ListWrapupRevisionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWrapupRevisionsResponse) ProtoMessage()    {}
+func (*ListWrapupRevisionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{13}
+}
+
+func (m *ListWrapupRevisionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWrapupRevisionsResponse.Unmarshal(m, b)
+}
+func (m *ListWrapupRevisionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWrapupRevisionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWrapupRevisionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWrapupRevisionsResponse.Merge(m, src)
+}
+func (m *ListWrapupRevisionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWrapupRevisionsResponse.Size(m)
+}
+func (m *ListWrapupRevisionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWrapupRevisionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWrapupRevisionsResponse proto.InternalMessageInfo
+
+func (m *ListWrapupRevisionsResponse) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ListWrapupRevisionsResponse) GetRevisions() []*WrapupRevision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+//*
+// GetWrapupRevisionRequest represents the request message for GetRevision operation.
+type GetWrapupRevisionRequest struct {
+	// id of wrapup object.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// revision number to fetch.
+	Revision             int32    `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWrapupRevisionRequest) Reset()         { *m = GetWrapupRevisionRequest{} }
+func (m *GetWrapupRevisionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWrapupRevisionRequest) ProtoMessage()    {}
+func (*GetWrapupRevisionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{14}
+}
+
+func (m *GetWrapupRevisionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWrapupRevisionRequest.Unmarshal(m, b)
+}
+func (m *GetWrapupRevisionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWrapupRevisionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWrapupRevisionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWrapupRevisionRequest.Merge(m, src)
+}
+func (m *GetWrapupRevisionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWrapupRevisionRequest.Size(m)
+}
+func (m *GetWrapupRevisionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWrapupRevisionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWrapupRevisionRequest proto.InternalMessageInfo
+
+func (m *GetWrapupRevisionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GetWrapupRevisionRequest) GetRevision() int32 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+//*
+// RollbackWrapupRequest represents the request message for Rollback operation.
+type RollbackWrapupRequest struct {
+	// id of wrapup object.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// revision number to restore.
+	Revision             int32    `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RollbackWrapupRequest) Reset()         { *m = RollbackWrapupRequest{} }
+func (m *RollbackWrapupRequest) String() string { return proto.CompactTextString(m) }
+func (*RollbackWrapupRequest) ProtoMessage()    {}
+func (*RollbackWrapupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{15}
+}
+
+func (m *RollbackWrapupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollbackWrapupRequest.Unmarshal(m, b)
+}
+func (m *RollbackWrapupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollbackWrapupRequest.Marshal(b, m, deterministic)
+}
+func (m *RollbackWrapupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollbackWrapupRequest.Merge(m, src)
+}
+func (m *RollbackWrapupRequest) XXX_Size() int {
+	return xxx_messageInfo_RollbackWrapupRequest.Size(m)
+}
+func (m *RollbackWrapupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollbackWrapupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollbackWrapupRequest proto.InternalMessageInfo
+
+func (m *RollbackWrapupRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RollbackWrapupRequest) GetRevision() int32 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+//*
+// Tag represents one tag and its usage.
+type Tag struct {
+	// name of the tag.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// number of wrapup objects which have this tag.
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Tag) Reset()         { *m = Tag{} }
+func (m *Tag) String() string { return proto.CompactTextString(m) }
+func (*Tag) ProtoMessage()    {}
+func (*Tag) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{16}
+}
+
+func (m *Tag) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Tag.Unmarshal(m, b)
+}
+func (m *Tag) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Tag.Marshal(b, m, deterministic)
+}
+func (m *Tag) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Tag.Merge(m, src)
+}
+func (m *Tag) XXX_Size() int {
+	return xxx_messageInfo_Tag.Size(m)
+}
+func (m *Tag) XXX_DiscardUnknown() {
+	xxx_messageInfo_Tag.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Tag proto.InternalMessageInfo
+
+func (m *Tag) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Tag) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+//*
+// ListTagsRequest represents the request message for ListTags operation.
+type ListTagsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTagsRequest) Reset()         { *m = ListTagsRequest{} }
+func (m *ListTagsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTagsRequest) ProtoMessage()    {}
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{17}
+}
+
+func (m *ListTagsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTagsRequest.Unmarshal(m, b)
+}
+func (m *ListTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTagsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTagsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTagsRequest.Merge(m, src)
+}
+func (m *ListTagsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTagsRequest.Size(m)
+}
+func (m *ListTagsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTagsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTagsRequest proto.InternalMessageInfo
+
+//*
+// ListTagsResponse represents the response of ListTags operation.
+type ListTagsResponse struct {
+	// list of tags ordered by the number of wrapup objects.
+	Tags                 []*Tag   `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTagsResponse) Reset()         { *m = ListTagsResponse{} }
+func (m *ListTagsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTagsResponse) ProtoMessage()    {}
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{18}
+}
+
+func (m *ListTagsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTagsResponse.Unmarshal(m, b)
+}
+func (m *ListTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTagsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTagsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTagsResponse.Merge(m, src)
+}
+func (m *ListTagsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTagsResponse.Size(m)
+}
+func (m *ListTagsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTagsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTagsResponse proto.InternalMessageInfo
+
+func (m *ListTagsResponse) GetTags() []*Tag {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+//*
+// RenameTagRequest represents the request message for RenameTag operation.
+type RenameTagRequest struct {
+	// current name of the tag.
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// new name of the tag.
+	NewTag               string   `protobuf:"bytes,2,opt,name=new_tag,json=newTag,proto3" json:"new_tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameTagRequest) Reset()         { *m = RenameTagRequest{} }
+func (m *RenameTagRequest) String() string { return proto.CompactTextString(m) }
+func (*RenameTagRequest) ProtoMessage()    {}
+func (*RenameTagRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{19}
+}
+
+func (m *RenameTagRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameTagRequest.Unmarshal(m, b)
+}
+func (m *RenameTagRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameTagRequest.Marshal(b, m, deterministic)
+}
+func (m *RenameTagRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameTagRequest.Merge(m, src)
+}
+func (m *RenameTagRequest) XXX_Size() int {
+	return xxx_messageInfo_RenameTagRequest.Size(m)
+}
+func (m *RenameTagRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameTagRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameTagRequest proto.InternalMessageInfo
+
+func (m *RenameTagRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *RenameTagRequest) GetNewTag() string {
+	if m != nil {
+		return m.NewTag
+	}
+	return ""
+}
+
+//*
+// RenameTagResponse represents the response of RenameTag operation.
+type RenameTagResponse struct {
+	// number of wrapup objects updated.
+	Updated              int64    `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameTagResponse) Reset()         { *m = RenameTagResponse{} }
+func (m *RenameTagResponse) String() string { return proto.CompactTextString(m) }
+func (*RenameTagResponse) ProtoMessage()    {}
+func (*RenameTagResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{20}
+}
+
+func (m *RenameTagResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameTagResponse.Unmarshal(m, b)
+}
+func (m *RenameTagResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameTagResponse.Marshal(b, m, deterministic)
+}
+func (m *RenameTagResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameTagResponse.Merge(m, src)
+}
+func (m *RenameTagResponse) XXX_Size() int {
+	return xxx_messageInfo_RenameTagResponse.Size(m)
+}
+func (m *RenameTagResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameTagResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameTagResponse proto.InternalMessageInfo
+
+func (m *RenameTagResponse) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+//*
+// SearchWrapupsRequest represents the request message for Search operation.
+type SearchWrapupsRequest struct {
+	// full-text query. e.g. "attention mechanism"
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// maximum number of results to return in one response.
+	// if not set, server default (50) is used. values above 1000 are coerced to 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token returned by the previous Search operation.
+	// if not set, the first page is returned.
+	PageToken            string   `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchWrapupsRequest) Reset()         { *m = SearchWrapupsRequest{} }
+func (m *SearchWrapupsRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchWrapupsRequest) ProtoMessage()    {}
+func (*SearchWrapupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{21}
+}
+
+func (m *SearchWrapupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchWrapupsRequest.Unmarshal(m, b)
+}
+func (m *SearchWrapupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchWrapupsRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchWrapupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchWrapupsRequest.Merge(m, src)
+}
+func (m *SearchWrapupsRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchWrapupsRequest.Size(m)
+}
+func (m *SearchWrapupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchWrapupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchWrapupsRequest proto.InternalMessageInfo
+
+func (m *SearchWrapupsRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *SearchWrapupsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *SearchWrapupsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+//*
+// SearchWrapupsResponse represents the response of Search operation.
+type SearchWrapupsResponse struct {
+	// list of search results ordered by relevance.
+	Results []*SearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// token to retrieve the next page of results.
+	// empty if there are no more results.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// total number of wrapup objects matched to the query across all pages.
+	TotalSize            int32    `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchWrapupsResponse) Reset()         { *m = SearchWrapupsResponse{} }
+func (m *SearchWrapupsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchWrapupsResponse) ProtoMessage()    {}
+func (*SearchWrapupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{22}
+}
+
+func (m *SearchWrapupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchWrapupsResponse.Unmarshal(m, b)
+}
+func (m *SearchWrapupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchWrapupsResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchWrapupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchWrapupsResponse.Merge(m, src)
+}
+func (m *SearchWrapupsResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchWrapupsResponse.Size(m)
+}
+func (m *SearchWrapupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchWrapupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchWrapupsResponse proto.InternalMessageInfo
+
+func (m *SearchWrapupsResponse) GetResults() []*SearchResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *SearchWrapupsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *SearchWrapupsResponse) GetTotalSize() int32 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+//*
+// SearchResult represents one wrapup object matched to the search query.
+type SearchResult struct {
+	// matched wrapup object.
+	Wrapup *Wrapup `protobuf:"bytes,1,opt,name=wrapup,proto3" json:"wrapup,omitempty"`
+	// relevance score of the wrapup object.
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// highlighted fragments of matched fields.
+	Highlights           []*Highlight `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SearchResult) Reset()         { *m = SearchResult{} }
+func (m *SearchResult) String() string { return proto.CompactTextString(m) }
+func (*SearchResult) ProtoMessage()    {}
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{23}
+}
+
+func (m *SearchResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResult.Unmarshal(m, b)
+}
+func (m *SearchResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResult.Marshal(b, m, deterministic)
+}
+func (m *SearchResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResult.Merge(m, src)
+}
+func (m *SearchResult) XXX_Size() int {
+	return xxx_messageInfo_SearchResult.Size(m)
+}
+func (m *SearchResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResult proto.InternalMessageInfo
+
+func (m *SearchResult) GetWrapup() *Wrapup {
+	if m != nil {
+		return m.Wrapup
+	}
+	return nil
+}
+
+func (m *SearchResult) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *SearchResult) GetHighlights() []*Highlight {
+	if m != nil {
+		return m.Highlights
+	}
+	return nil
+}
+
+//*
+// Highlight represents the highlighted fragments of one field.
+type Highlight struct {
+	// name of the matched field. e.g. "title", "metadata.abstract"
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// text fragments which contain matched terms.
+	// matched terms are enclosed with <em> and </em>.
+	Fragments            []string `protobuf:"bytes,2,rep,name=fragments,proto3" json:"fragments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Highlight) Reset()         { *m = Highlight{} }
+func (m *Highlight) String() string { return proto.CompactTextString(m) }
+func (*Highlight) ProtoMessage()    {}
+func (*Highlight) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{24}
+}
+
+func (m *Highlight) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Highlight.Unmarshal(m, b)
+}
+func (m *Highlight) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Highlight.Marshal(b, m, deterministic)
+}
+func (m *Highlight) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Highlight.Merge(m, src)
+}
+func (m *Highlight) XXX_Size() int {
+	return xxx_messageInfo_Highlight.Size(m)
+}
+func (m *Highlight) XXX_DiscardUnknown() {
+	xxx_messageInfo_Highlight.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Highlight proto.InternalMessageInfo
+
+func (m *Highlight) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *Highlight) GetFragments() []string {
+	if m != nil {
+		return m.Fragments
+	}
+	return nil
+}
+
+//*
+// AggregateWrapupsRequest represents the request message for Aggregate operation.
+type AggregateWrapupsRequest struct {
+	// filter is used to aggregate only matched wrapup documents.
+	// the syntax is the same as ListWrapupsRequest.filter.
+	Filter string `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// number of significant terms returned for each month.
+	// if not set, server default (5) is used.
+	SignificantTermsSize int32    `protobuf:"varint,2,opt,name=significant_terms_size,json=significantTermsSize,proto3" json:"significant_terms_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AggregateWrapupsRequest) Reset()         { *m = AggregateWrapupsRequest{} }
+func (m *AggregateWrapupsRequest) String() string { return proto.CompactTextString(m) }
+func (*AggregateWrapupsRequest) ProtoMessage()    {}
+func (*AggregateWrapupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{25}
+}
+
+func (m *AggregateWrapupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AggregateWrapupsRequest.Unmarshal(m, b)
+}
+func (m *AggregateWrapupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AggregateWrapupsRequest.Marshal(b, m, deterministic)
+}
+func (m *AggregateWrapupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregateWrapupsRequest.Merge(m, src)
+}
+func (m *AggregateWrapupsRequest) XXX_Size() int {
+	return xxx_messageInfo_AggregateWrapupsRequest.Size(m)
+}
+func (m *AggregateWrapupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregateWrapupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregateWrapupsRequest proto.InternalMessageInfo
+
+func (m *AggregateWrapupsRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *AggregateWrapupsRequest) GetSignificantTermsSize() int32 {
+	if m != nil {
+		return m.SignificantTermsSize
+	}
+	return 0
+}
+
+//*
+// AggregateWrapupsResponse represents the response of Aggregate operation.
+type AggregateWrapupsResponse struct {
+	// number of wrapup objects aggregated.
+	TotalSize int64 `protobuf:"varint,1,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	// number of wrapup objects per month of create_time, ordered by month.
+	Monthly []*PeriodBucket `protobuf:"bytes,2,rep,name=monthly,proto3" json:"monthly,omitempty"`
+	// number of wrapup objects per tag, ordered by count.
+	Tags []*TermBucket `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	// number of wrapup objects per length of wrapup text, ordered by length.
+	WrapupLengths        []*RangeBucket `protobuf:"bytes,4,rep,name=wrapup_lengths,json=wrapupLengths,proto3" json:"wrapup_lengths,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AggregateWrapupsResponse) Reset()         { *m = AggregateWrapupsResponse{} }
+func (m *AggregateWrapupsResponse) String() string { return proto.CompactTextString(m) }
+func (*AggregateWrapupsResponse) ProtoMessage()    {}
+func (*AggregateWrapupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{26}
+}
+
+func (m *AggregateWrapupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AggregateWrapupsResponse.Unmarshal(m, b)
+}
+func (m *AggregateWrapupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AggregateWrapupsResponse.Marshal(b, m, deterministic)
+}
+func (m *AggregateWrapupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregateWrapupsResponse.Merge(m, src)
+}
+func (m *AggregateWrapupsResponse) XXX_Size() int {
+	return xxx_messageInfo_AggregateWrapupsResponse.Size(m)
+}
+func (m *AggregateWrapupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregateWrapupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregateWrapupsResponse proto.InternalMessageInfo
+
+func (m *AggregateWrapupsResponse) GetTotalSize() int64 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+func (m *AggregateWrapupsResponse) GetMonthly() []*PeriodBucket {
+	if m != nil {
+		return m.Monthly
+	}
+	return nil
+}
+
+func (m *AggregateWrapupsResponse) GetTags() []*TermBucket {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *AggregateWrapupsResponse) GetWrapupLengths() []*RangeBucket {
+	if m != nil {
+		return m.WrapupLengths
+	}
+	return nil
+}
+
+//*
+// PeriodBucket represents the aggregation of one period.
+type PeriodBucket struct {
+	// start of the period.
+	StartTime *timestamp.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// number of wrapup objects created in the period.
+	Count int64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// terms in wrapup text which characterize the period compared to the whole corpus.
+	SignificantTerms     []*TermBucket `protobuf:"bytes,3,rep,name=significant_terms,json=significantTerms,proto3" json:"significant_terms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *PeriodBucket) Reset()         { *m = PeriodBucket{} }
+func (m *PeriodBucket) String() string { return proto.CompactTextString(m) }
+func (*PeriodBucket) ProtoMessage()    {}
+func (*PeriodBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{27}
+}
+
+func (m *PeriodBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeriodBucket.Unmarshal(m, b)
+}
+func (m *PeriodBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeriodBucket.Marshal(b, m, deterministic)
+}
+func (m *PeriodBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeriodBucket.Merge(m, src)
+}
+func (m *PeriodBucket) XXX_Size() int {
+	return xxx_messageInfo_PeriodBucket.Size(m)
+}
+func (m *PeriodBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeriodBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeriodBucket proto.InternalMessageInfo
+
+func (m *PeriodBucket) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *PeriodBucket) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *PeriodBucket) GetSignificantTerms() []*TermBucket {
+	if m != nil {
+		return m.SignificantTerms
+	}
+	return nil
+}
+
+//*
+// TermBucket represents the aggregation of one term.
+type TermBucket struct {
+	// term.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// number of wrapup objects which contain the term.
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TermBucket) Reset()         { *m = TermBucket{} }
+func (m *TermBucket) String() string { return proto.CompactTextString(m) }
+func (*TermBucket) ProtoMessage()    {}
+func (*TermBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{28}
+}
+
+func (m *TermBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TermBucket.Unmarshal(m, b)
+}
+func (m *TermBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TermBucket.Marshal(b, m, deterministic)
+}
+func (m *TermBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TermBucket.Merge(m, src)
+}
+func (m *TermBucket) XXX_Size() int {
+	return xxx_messageInfo_TermBucket.Size(m)
+}
+func (m *TermBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_TermBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TermBucket proto.InternalMessageInfo
+
+func (m *TermBucket) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TermBucket) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+//*
+// RangeBucket represents the aggregation of one numeric range [from, to).
+type RangeBucket struct {
+	// lower bound of the range, inclusive.
+	From int64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	// upper bound of the range, exclusive. 0 means unbounded.
+	To int64 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	// number of wrapup objects in the range.
+	Count                int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RangeBucket) Reset()         { *m = RangeBucket{} }
+func (m *RangeBucket) String() string { return proto.CompactTextString(m) }
+func (*RangeBucket) ProtoMessage()    {}
+func (*RangeBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{29}
+}
+
+func (m *RangeBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RangeBucket.Unmarshal(m, b)
+}
+func (m *RangeBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RangeBucket.Marshal(b, m, deterministic)
+}
+func (m *RangeBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RangeBucket.Merge(m, src)
+}
+func (m *RangeBucket) XXX_Size() int {
+	return xxx_messageInfo_RangeBucket.Size(m)
+}
+func (m *RangeBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_RangeBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RangeBucket proto.InternalMessageInfo
+
+func (m *RangeBucket) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *RangeBucket) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *RangeBucket) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+//*
+// FindRelatedWrapupsRequest represents the request message for FindRelated operation.
+type FindRelatedWrapupsRequest struct {
+	// ID of the wrapup object which related ones are searched for.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// maximum number of related wrapup objects.
+	// if not set, server default (10) is used.
+	Size                 int32    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindRelatedWrapupsRequest) Reset()         { *m = FindRelatedWrapupsRequest{} }
+func (m *FindRelatedWrapupsRequest) String() string { return proto.CompactTextString(m) }
+func (*FindRelatedWrapupsRequest) ProtoMessage()    {}
+func (*FindRelatedWrapupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{30}
+}
+
+func (m *FindRelatedWrapupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindRelatedWrapupsRequest.Unmarshal(m, b)
+}
+func (m *FindRelatedWrapupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindRelatedWrapupsRequest.Marshal(b, m, deterministic)
+}
+func (m *FindRelatedWrapupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindRelatedWrapupsRequest.Merge(m, src)
+}
+func (m *FindRelatedWrapupsRequest) XXX_Size() int {
+	return xxx_messageInfo_FindRelatedWrapupsRequest.Size(m)
+}
+func (m *FindRelatedWrapupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindRelatedWrapupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindRelatedWrapupsRequest proto.InternalMessageInfo
+
+func (m *FindRelatedWrapupsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *FindRelatedWrapupsRequest) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+//*
+// FindRelatedWrapupsResponse represents the response of FindRelated operation.
+type FindRelatedWrapupsResponse struct {
+	// related wrapup objects ordered by similarity.
+	Related              []*RelatedWrapup `protobuf:"bytes,1,rep,name=related,proto3" json:"related,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *FindRelatedWrapupsResponse) Reset()         { *m = FindRelatedWrapupsResponse{} }
+func (m *FindRelatedWrapupsResponse) String() string { return proto.CompactTextString(m) }
+func (*FindRelatedWrapupsResponse) ProtoMessage()    {}
+func (*FindRelatedWrapupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{31}
+}
+
+func (m *FindRelatedWrapupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindRelatedWrapupsResponse.Unmarshal(m, b)
+}
+func (m *FindRelatedWrapupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindRelatedWrapupsResponse.Marshal(b, m, deterministic)
+}
+func (m *FindRelatedWrapupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindRelatedWrapupsResponse.Merge(m, src)
+}
+func (m *FindRelatedWrapupsResponse) XXX_Size() int {
+	return xxx_messageInfo_FindRelatedWrapupsResponse.Size(m)
+}
+func (m *FindRelatedWrapupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindRelatedWrapupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindRelatedWrapupsResponse proto.InternalMessageInfo
+
+func (m *FindRelatedWrapupsResponse) GetRelated() []*RelatedWrapup {
+	if m != nil {
+		return m.Related
+	}
+	return nil
+}
+
+//*
+// RelatedWrapup represents one wrapup object related to the requested one.
+type RelatedWrapup struct {
+	// related wrapup object.
+	Wrapup *Wrapup `protobuf:"bytes,1,opt,name=wrapup,proto3" json:"wrapup,omitempty"`
+	// similarity score of the wrapup object.
+	Score                float64  `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RelatedWrapup) Reset()         { *m = RelatedWrapup{} }
+func (m *RelatedWrapup) String() string { return proto.CompactTextString(m) }
+func (*RelatedWrapup) ProtoMessage()    {}
+func (*RelatedWrapup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{32}
+}
+
+func (m *RelatedWrapup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RelatedWrapup.Unmarshal(m, b)
+}
+func (m *RelatedWrapup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RelatedWrapup.Marshal(b, m, deterministic)
+}
+func (m *RelatedWrapup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RelatedWrapup.Merge(m, src)
+}
+func (m *RelatedWrapup) XXX_Size() int {
+	return xxx_messageInfo_RelatedWrapup.Size(m)
+}
+func (m *RelatedWrapup) XXX_DiscardUnknown() {
+	xxx_messageInfo_RelatedWrapup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RelatedWrapup proto.InternalMessageInfo
+
+func (m *RelatedWrapup) GetWrapup() *Wrapup {
+	if m != nil {
+		return m.Wrapup
+	}
+	return nil
+}
+
+func (m *RelatedWrapup) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+//*
+// SuggestTitlesRequest represents the request message for SuggestTitles operation.
+type SuggestTitlesRequest struct {
+	// prefix of the title.
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// maximum number of suggestions.
+	// if not set, server default (5) is used.
+	Size                 int32    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SuggestTitlesRequest) Reset()         { *m = SuggestTitlesRequest{} }
+func (m *SuggestTitlesRequest) String() string { return proto.CompactTextString(m) }
+func (*SuggestTitlesRequest) ProtoMessage()    {}
+func (*SuggestTitlesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{33}
+}
+
+func (m *SuggestTitlesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SuggestTitlesRequest.Unmarshal(m, b)
+}
+func (m *SuggestTitlesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SuggestTitlesRequest.Marshal(b, m, deterministic)
+}
+func (m *SuggestTitlesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SuggestTitlesRequest.Merge(m, src)
+}
+func (m *SuggestTitlesRequest) XXX_Size() int {
+	return xxx_messageInfo_SuggestTitlesRequest.Size(m)
+}
+func (m *SuggestTitlesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SuggestTitlesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SuggestTitlesRequest proto.InternalMessageInfo
+
+func (m *SuggestTitlesRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *SuggestTitlesRequest) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+//*
+// SuggestTitlesResponse represents the response of SuggestTitles operation.
+type SuggestTitlesResponse struct {
+	// suggested titles ordered by score.
+	Suggestions          []*TitleSuggestion `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *SuggestTitlesResponse) Reset()         { *m = SuggestTitlesResponse{} }
+func (m *SuggestTitlesResponse) String() string { return proto.CompactTextString(m) }
+func (*SuggestTitlesResponse) ProtoMessage()    {}
+func (*SuggestTitlesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{34}
+}
+
+func (m *SuggestTitlesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SuggestTitlesResponse.Unmarshal(m, b)
+}
+func (m *SuggestTitlesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SuggestTitlesResponse.Marshal(b, m, deterministic)
+}
+func (m *SuggestTitlesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SuggestTitlesResponse.Merge(m, src)
+}
+func (m *SuggestTitlesResponse) XXX_Size() int {
+	return xxx_messageInfo_SuggestTitlesResponse.Size(m)
+}
+func (m *SuggestTitlesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SuggestTitlesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SuggestTitlesResponse proto.InternalMessageInfo
+
+func (m *SuggestTitlesResponse) GetSuggestions() []*TitleSuggestion {
+	if m != nil {
+		return m.Suggestions
+	}
+	return nil
+}
+
+//*
+// TitleSuggestion represents one suggested title.
+type TitleSuggestion struct {
+	// ID of the wrapup object.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// title of the wrapup object.
+	Title                string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TitleSuggestion) Reset()         { *m = TitleSuggestion{} }
+func (m *TitleSuggestion) String() string { return proto.CompactTextString(m) }
+func (*TitleSuggestion) ProtoMessage()    {}
+func (*TitleSuggestion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{35}
+}
+
+func (m *TitleSuggestion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TitleSuggestion.Unmarshal(m, b)
+}
+func (m *TitleSuggestion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TitleSuggestion.Marshal(b, m, deterministic)
+}
+func (m *TitleSuggestion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TitleSuggestion.Merge(m, src)
+}
+func (m *TitleSuggestion) XXX_Size() int {
+	return xxx_messageInfo_TitleSuggestion.Size(m)
+}
+func (m *TitleSuggestion) XXX_DiscardUnknown() {
+	xxx_messageInfo_TitleSuggestion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TitleSuggestion proto.InternalMessageInfo
+
+func (m *TitleSuggestion) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TitleSuggestion) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+//*
+// ReindexWrapupsRequest represents the request of ReindexWrapups.
+type ReindexWrapupsRequest struct {
+	// whether the old index is deleted after the alias is switched.
+	// if false, the old index is kept for rollback.
+	DeleteOldIndex       bool     `protobuf:"varint,1,opt,name=delete_old_index,json=deleteOldIndex,proto3" json:"delete_old_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReindexWrapupsRequest) Reset()         { *m = ReindexWrapupsRequest{} }
+func (m *ReindexWrapupsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReindexWrapupsRequest) ProtoMessage()    {}
+func (*ReindexWrapupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{36}
+}
+
+func (m *ReindexWrapupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReindexWrapupsRequest.Unmarshal(m, b)
+}
+func (m *ReindexWrapupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReindexWrapupsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReindexWrapupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReindexWrapupsRequest.Merge(m, src)
+}
+func (m *ReindexWrapupsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReindexWrapupsRequest.Size(m)
+}
+func (m *ReindexWrapupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReindexWrapupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReindexWrapupsRequest proto.InternalMessageInfo
+
+func (m *ReindexWrapupsRequest) GetDeleteOldIndex() bool {
+	if m != nil {
+		return m.DeleteOldIndex
+	}
+	return false
+}
+
+//*
+// ReindexProgress represents the progress of ReindexWrapups.
+type ReindexProgress struct {
+	// current step.
+	Step ReindexProgress_Step `protobuf:"varint,1,opt,name=step,proto3,enum=wrapups.ReindexProgress_Step" json:"step,omitempty"`
+	// name of the index which the alias pointed to.
+	SourceIndex string `protobuf:"bytes,2,opt,name=source_index,json=sourceIndex,proto3" json:"source_index,omitempty"`
+	// name of new index.
+	TargetIndex string `protobuf:"bytes,3,opt,name=target_index,json=targetIndex,proto3" json:"target_index,omitempty"`
+	// number of documents to copy.
+	Total int64 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	// number of documents copied so far.
+	Copied               int64    `protobuf:"varint,5,opt,name=copied,proto3" json:"copied,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReindexProgress) Reset()         { *m = ReindexProgress{} }
+func (m *ReindexProgress) String() string { return proto.CompactTextString(m) }
+func (*ReindexProgress) ProtoMessage()    {}
+func (*ReindexProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{37}
+}
+
+func (m *ReindexProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReindexProgress.Unmarshal(m, b)
+}
+func (m *ReindexProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReindexProgress.Marshal(b, m, deterministic)
+}
+func (m *ReindexProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReindexProgress.Merge(m, src)
+}
+func (m *ReindexProgress) XXX_Size() int {
+	return xxx_messageInfo_ReindexProgress.Size(m)
+}
+func (m *ReindexProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReindexProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReindexProgress proto.InternalMessageInfo
+
+func (m *ReindexProgress) GetStep() ReindexProgress_Step {
+	if m != nil {
+		return m.Step
+	}
+	return ReindexProgress_STEP_UNSPECIFIED
+}
+
+func (m *ReindexProgress) GetSourceIndex() string {
+	if m != nil {
+		return m.SourceIndex
+	}
+	return ""
+}
+
+func (m *ReindexProgress) GetTargetIndex() string {
+	if m != nil {
+		return m.TargetIndex
+	}
+	return ""
+}
+
+func (m *ReindexProgress) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *ReindexProgress) GetCopied() int64 {
+	if m != nil {
+		return m.Copied
+	}
+	return 0
+}
+
+//*
+// AuthenticationFailure is attached to the details of Unauthenticated status.
+type AuthenticationFailure struct {
+	// why the request is not authenticated.
+	Reason               AuthenticationFailure_Reason `protobuf:"varint,1,opt,name=reason,proto3,enum=wrapups.AuthenticationFailure_Reason" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *AuthenticationFailure) Reset()         { *m = AuthenticationFailure{} }
+func (m *AuthenticationFailure) String() string { return proto.CompactTextString(m) }
+func (*AuthenticationFailure) ProtoMessage()    {}
+func (*AuthenticationFailure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{38}
+}
+
+func (m *AuthenticationFailure) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticationFailure.Unmarshal(m, b)
+}
+func (m *AuthenticationFailure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticationFailure.Marshal(b, m, deterministic)
+}
+func (m *AuthenticationFailure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticationFailure.Merge(m, src)
+}
+func (m *AuthenticationFailure) XXX_Size() int {
+	return xxx_messageInfo_AuthenticationFailure.Size(m)
+}
+func (m *AuthenticationFailure) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticationFailure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticationFailure proto.InternalMessageInfo
+
+func (m *AuthenticationFailure) GetReason() AuthenticationFailure_Reason {
+	if m != nil {
+		return m.Reason
+	}
+	return AuthenticationFailure_REASON_UNSPECIFIED
+}
+
+//*
+// BackfillOwnersRequest represents the request of BackfillOwners.
+type BackfillOwnersRequest struct {
+	// user set to created_by and updated_by of wrapup objects which do not have them.
+	User                 string   `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackfillOwnersRequest) Reset()         { *m = BackfillOwnersRequest{} }
+func (m *BackfillOwnersRequest) String() string { return proto.CompactTextString(m) }
+func (*BackfillOwnersRequest) ProtoMessage()    {}
+func (*BackfillOwnersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{39}
+}
+
+func (m *BackfillOwnersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackfillOwnersRequest.Unmarshal(m, b)
+}
+func (m *BackfillOwnersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackfillOwnersRequest.Marshal(b, m, deterministic)
+}
+func (m *BackfillOwnersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackfillOwnersRequest.Merge(m, src)
+}
+func (m *BackfillOwnersRequest) XXX_Size() int {
+	return xxx_messageInfo_BackfillOwnersRequest.Size(m)
+}
+func (m *BackfillOwnersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackfillOwnersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackfillOwnersRequest proto.InternalMessageInfo
+
+func (m *BackfillOwnersRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+//*
+// BackfillOwnersResponse represents the response of BackfillOwners.
+type BackfillOwnersResponse struct {
+	// number of updated wrapup objects.
+	Updated              int64    `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackfillOwnersResponse) Reset()         { *m = BackfillOwnersResponse{} }
+func (m *BackfillOwnersResponse) String() string { return proto.CompactTextString(m) }
+func (*BackfillOwnersResponse) ProtoMessage()    {}
+func (*BackfillOwnersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_685ed5c71b2573e9, []int{40}
+}
+
+func (m *BackfillOwnersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackfillOwnersResponse.Unmarshal(m, b)
+}
+func (m *BackfillOwnersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackfillOwnersResponse.Marshal(b, m, deterministic)
+}
+func (m *BackfillOwnersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackfillOwnersResponse.Merge(m, src)
+}
+func (m *BackfillOwnersResponse) XXX_Size() int {
+	return xxx_messageInfo_BackfillOwnersResponse.Size(m)
+}
+func (m *BackfillOwnersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackfillOwnersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackfillOwnersResponse proto.InternalMessageInfo
+
+func (m *BackfillOwnersResponse) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("wrapups.ReindexProgress_Step", ReindexProgress_Step_name, ReindexProgress_Step_value)
+	proto.RegisterEnum("wrapups.AuthenticationFailure_Reason", AuthenticationFailure_Reason_name, AuthenticationFailure_Reason_value)
+	proto.RegisterType((*Wrapup)(nil), "wrapups.Wrapup")
+	proto.RegisterType((*PaperMetadata)(nil), "wrapups.PaperMetadata")
+	proto.RegisterType((*ListWrapupsRequest)(nil), "wrapups.ListWrapupsRequest")
+	proto.RegisterType((*ListWrapupsResponse)(nil), "wrapups.ListWrapupsResponse")
+	proto.RegisterType((*GetWrapupRequest)(nil), "wrapups.GetWrapupRequest")
+	proto.RegisterType((*CreateWrapupRequest)(nil), "wrapups.CreateWrapupRequest")
+	proto.RegisterType((*DuplicateTitle)(nil), "wrapups.DuplicateTitle")
+	proto.RegisterType((*UpdateWrapupRequest)(nil), "wrapups.UpdateWrapupRequest")
+	proto.RegisterType((*DeleteWrapupRequest)(nil), "wrapups.DeleteWrapupRequest")
+	proto.RegisterType((*UndeleteWrapupRequest)(nil), "wrapups.UndeleteWrapupRequest")
+	proto.RegisterType((*ListDeletedWrapupsRequest)(nil), "wrapups.ListDeletedWrapupsRequest")
+	proto.RegisterType((*WrapupRevision)(nil), "wrapups.WrapupRevision")
+	proto.RegisterType((*ListWrapupRevisionsRequest)(nil), "wrapups.ListWrapupRevisionsRequest")
+	proto.RegisterType((*ListWrapupRevisionsResponse)(nil), "wrapups.ListWrapupRevisionsResponse")
+	proto.RegisterType((*GetWrapupRevisionRequest)(nil), "wrapups.GetWrapupRevisionRequest")
+	proto.RegisterType((*RollbackWrapupRequest)(nil), "wrapups.RollbackWrapupRequest")
+	proto.RegisterType((*Tag)(nil), "wrapups.Tag")
+	proto.RegisterType((*ListTagsRequest)(nil), "wrapups.ListTagsRequest")
+	proto.RegisterType((*ListTagsResponse)(nil), "wrapups.ListTagsResponse")
+	proto.RegisterType((*RenameTagRequest)(nil), "wrapups.RenameTagRequest")
+	proto.RegisterType((*RenameTagResponse)(nil), "wrapups.RenameTagResponse")
+	proto.RegisterType((*SearchWrapupsRequest)(nil), "wrapups.SearchWrapupsRequest")
+	proto.RegisterType((*SearchWrapupsResponse)(nil), "wrapups.SearchWrapupsResponse")
+	proto.RegisterType((*SearchResult)(nil), "wrapups.SearchResult")
+	proto.RegisterType((*Highlight)(nil), "wrapups.Highlight")
+	proto.RegisterType((*AggregateWrapupsRequest)(nil), "wrapups.AggregateWrapupsRequest")
+	proto.RegisterType((*AggregateWrapupsResponse)(nil), "wrapups.AggregateWrapupsResponse")
+	proto.RegisterType((*PeriodBucket)(nil), "wrapups.PeriodBucket")
+	proto.RegisterType((*TermBucket)(nil), "wrapups.TermBucket")
+	proto.RegisterType((*RangeBucket)(nil), "wrapups.RangeBucket")
+	proto.RegisterType((*FindRelatedWrapupsRequest)(nil), "wrapups.FindRelatedWrapupsRequest")
+	proto.RegisterType((*FindRelatedWrapupsResponse)(nil), "wrapups.FindRelatedWrapupsResponse")
+	proto.RegisterType((*RelatedWrapup)(nil), "wrapups.RelatedWrapup")
+	proto.RegisterType((*SuggestTitlesRequest)(nil), "wrapups.SuggestTitlesRequest")
+	proto.RegisterType((*SuggestTitlesResponse)(nil), "wrapups.SuggestTitlesResponse")
+	proto.RegisterType((*TitleSuggestion)(nil), "wrapups.TitleSuggestion")
+	proto.RegisterType((*ReindexWrapupsRequest)(nil), "wrapups.ReindexWrapupsRequest")
+	proto.RegisterType((*ReindexProgress)(nil), "wrapups.ReindexProgress")
+	proto.RegisterType((*AuthenticationFailure)(nil), "wrapups.AuthenticationFailure")
+	proto.RegisterType((*BackfillOwnersRequest)(nil), "wrapups.BackfillOwnersRequest")
+	proto.RegisterType((*BackfillOwnersResponse)(nil), "wrapups.BackfillOwnersResponse")
+}
+
+func init() { proto.RegisterFile("pkg/wrapups/wrapups.proto", fileDescriptor_685ed5c71b2573e9) }
+
+var fileDescriptor_685ed5c71b2573e9 = []byte{
+	// 2052 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcf, 0x72, 0xe3, 0x48,
+	0x19, 0x1f, 0x59, 0x76, 0x62, 0x7f, 0x49, 0x1c, 0x4f, 0x4f, 0x92, 0x51, 0x3c, 0x3b, 0xbb, 0x19,
+	0x2d, 0xcb, 0x84, 0x02, 0x92, 0x25, 0x0c, 0x50, 0xcb, 0xd6, 0xd6, 0xe0, 0x24, 0xce, 0xac, 0x21,
+	0xe3, 0x84, 0xb6, 0x67, 0x77, 0xe6, 0xe4, 0x52, 0xac, 0x8e, 0x22, 0x22, 0x4b, 0x5e, 0xa9, 0x9d,
+	0x4c, 0xf6, 0xc6, 0x13, 0xf0, 0x04, 0x1c, 0xa8, 0xe2, 0x4c, 0x41, 0xf1, 0x02, 0xdc, 0xb9, 0x70,
+	0xe3, 0xce, 0x53, 0x70, 0xa4, 0xfa, 0x9f, 0xdc, 0x52, 0x64, 0x27, 0xd4, 0x9e, 0xac, 0xef, 0x6f,
+	0x7f, 0x7f, 0xba, 0x7f, 0xdd, 0x9f, 0x61, 0x73, 0x7c, 0xe9, 0xed, 0x5e, 0xc7, 0xce, 0x78, 0x32,
+	0x4e, 0xd4, 0xef, 0xce, 0x38, 0x8e, 0x68, 0x84, 0x16, 0x25, 0xd9, 0xdc, 0xf2, 0xa2, 0xc8, 0x0b,
+	0xc8, 0x2e, 0x67, 0x9f, 0x4d, 0xce, 0x77, 0xcf, 0x7d, 0x12, 0xb8, 0x83, 0x91, 0x93, 0x5c, 0x0a,
+	0xd5, 0xe6, 0x47, 0x79, 0x0d, 0xea, 0x8f, 0x48, 0x42, 0x9d, 0xd1, 0x58, 0x28, 0xd8, 0xff, 0x34,
+	0x61, 0xe1, 0x6b, 0xee, 0x0e, 0xd5, 0xa1, 0xe4, 0xbb, 0x96, 0xb1, 0x65, 0x6c, 0xd7, 0x70, 0xc9,
+	0x77, 0xd1, 0x1a, 0x54, 0xa8, 0x4f, 0x03, 0x62, 0x95, 0x38, 0x4b, 0x10, 0x68, 0x03, 0x16, 0xc4,
+	0xf2, 0x96, 0xc9, 0xd9, 0x92, 0x42, 0x16, 0x2c, 0x0e, 0xa3, 0xd1, 0x88, 0x84, 0xd4, 0x2a, 0x73,
+	0x81, 0x22, 0x11, 0x82, 0x72, 0x18, 0x51, 0x62, 0x55, 0x38, 0x9b, 0x7f, 0xa3, 0xcf, 0x61, 0x69,
+	0x18, 0x13, 0x87, 0x92, 0x01, 0x0b, 0xc8, 0x5a, 0xd8, 0x32, 0xb6, 0x97, 0xf6, 0x9a, 0x3b, 0x22,
+	0xda, 0x1d, 0x15, 0xed, 0x4e, 0x5f, 0x45, 0x8b, 0x41, 0xa8, 0x33, 0x06, 0x33, 0x9e, 0x8c, 0xdd,
+	0xd4, 0x78, 0xf1, 0x6e, 0x63, 0xa1, 0xae, 0x8c, 0x5d, 0x12, 0x10, 0x65, 0x5c, 0xbd, 0xdb, 0x58,
+	0xa8, 0x73, 0xe3, 0x3d, 0xa8, 0x8e, 0x08, 0x75, 0x5c, 0x87, 0x3a, 0x56, 0x8d, 0x5b, 0x6e, 0xec,
+	0xa8, 0xde, 0x9c, 0x3a, 0x63, 0x12, 0xbf, 0x96, 0x52, 0x9c, 0xea, 0xb1, 0xf4, 0xa9, 0xe3, 0x25,
+	0x16, 0x6c, 0x99, 0x2c, 0x7d, 0xf6, 0x8d, 0x9e, 0x82, 0xcc, 0xc7, 0x1d, 0x9c, 0xdd, 0x58, 0x4b,
+	0xbc, 0x30, 0x35, 0xc9, 0xd9, 0xbf, 0x61, 0x62, 0x11, 0x31, 0x17, 0x2f, 0x0b, 0xb1, 0xe4, 0xec,
+	0xdf, 0xb0, 0x52, 0x5f, 0x91, 0x38, 0xf1, 0xa3, 0xd0, 0x5a, 0xd9, 0x32, 0xb6, 0x4d, 0xac, 0x48,
+	0xfb, 0x2f, 0x06, 0xac, 0x64, 0xe2, 0x60, 0xba, 0xce, 0x84, 0x5e, 0x44, 0x71, 0x62, 0x19, 0x3c,
+	0x00, 0x45, 0xb2, 0xf6, 0x5e, 0x91, 0x70, 0x92, 0xb6, 0x97, 0x13, 0x2c, 0xda, 0x1b, 0xe2, 0xc4,
+	0xbc, 0xb9, 0x15, 0xcc, 0xbf, 0x51, 0x03, 0x4c, 0x37, 0xf2, 0x65, 0x5b, 0xd9, 0x27, 0xda, 0x84,
+	0xaa, 0x13, 0xbf, 0xf7, 0xaf, 0x06, 0xbe, 0x2b, 0xdb, 0xba, 0xc8, 0xe9, 0x8e, 0xcb, 0x94, 0x27,
+	0x71, 0xc0, 0x3b, 0x5a, 0xc3, 0xec, 0x13, 0x35, 0xa1, 0xea, 0x9c, 0x25, 0x34, 0x76, 0x86, 0x94,
+	0xf7, 0xaa, 0x86, 0x53, 0xda, 0xfe, 0xaf, 0x01, 0xe8, 0xd8, 0x4f, 0xa8, 0xd8, 0x82, 0x09, 0x26,
+	0xdf, 0x4c, 0x48, 0x42, 0xd9, 0x26, 0x3b, 0xf7, 0x03, 0x4a, 0x62, 0xb9, 0x1d, 0x25, 0xc5, 0xf8,
+	0x22, 0x7c, 0x19, 0xb4, 0xa4, 0xa6, 0xb9, 0x98, 0x45, 0xb9, 0x94, 0xb5, 0x5c, 0x54, 0x37, 0x2a,
+	0x5a, 0x37, 0x9e, 0x40, 0x6d, 0xec, 0x78, 0x64, 0x90, 0xf8, 0xdf, 0x8a, 0xad, 0x58, 0xc1, 0x55,
+	0xc6, 0xe8, 0xf9, 0xdf, 0x12, 0xd6, 0x0b, 0x2e, 0xa4, 0xd1, 0x25, 0x09, 0x65, 0xfc, 0x5c, 0xbd,
+	0xcf, 0x18, 0xac, 0x12, 0x51, 0xec, 0x92, 0x98, 0x35, 0xaa, 0x2a, 0x2a, 0xc1, 0x69, 0xd1, 0x45,
+	0xad, 0xc9, 0xb5, 0x5c, 0x93, 0xed, 0x3f, 0x1a, 0xf0, 0x28, 0x93, 0x7a, 0x32, 0x8e, 0xc2, 0x84,
+	0xb0, 0x5c, 0x86, 0xd1, 0x24, 0xa4, 0x3c, 0xf5, 0x0a, 0x16, 0x04, 0xfa, 0x01, 0xa8, 0x53, 0x6f,
+	0x95, 0xb6, 0xcc, 0xed, 0xa5, 0xbd, 0xd5, 0x74, 0xe3, 0x09, 0x07, 0x58, 0xc9, 0xd1, 0xf7, 0x61,
+	0x35, 0x24, 0xef, 0xe9, 0x40, 0x0b, 0x5b, 0x94, 0x65, 0x85, 0xb1, 0x4f, 0xd3, 0xd0, 0x9f, 0x02,
+	0xd0, 0x88, 0x3a, 0x81, 0xc8, 0x5b, 0x14, 0xa9, 0xc6, 0x39, 0x2c, 0x71, 0xdb, 0x86, 0xc6, 0x2b,
+	0x22, 0xa3, 0x53, 0x7d, 0xc9, 0x41, 0x84, 0xfd, 0x1f, 0x03, 0x1e, 0x1d, 0xf0, 0x8c, 0xb2, 0x7a,
+	0x29, 0x74, 0x18, 0xc5, 0xd0, 0x51, 0x9a, 0x05, 0x1d, 0x66, 0x31, 0x74, 0x94, 0x35, 0xe8, 0xd0,
+	0xcf, 0x60, 0xe5, 0xff, 0x3c, 0x83, 0x0b, 0x5a, 0xd7, 0x9f, 0xc3, 0xaa, 0x13, 0x04, 0xd1, 0xf5,
+	0xc0, 0x9d, 0x8c, 0x03, 0x7f, 0xe8, 0x50, 0x81, 0x24, 0x55, 0x5c, 0xe7, 0xec, 0x43, 0xc5, 0xb5,
+	0x7f, 0x0e, 0xf5, 0x94, 0xe8, 0xf3, 0x44, 0xee, 0x85, 0x94, 0xf6, 0x9f, 0x0d, 0x78, 0xf4, 0x86,
+	0x1f, 0xda, 0x6c, 0x71, 0x9e, 0xa7, 0x65, 0x30, 0x78, 0xf8, 0xb7, 0x3a, 0xa9, 0xea, 0x32, 0xc5,
+	0x39, 0x86, 0xe8, 0xdc, 0x79, 0x11, 0x54, 0x1d, 0x31, 0xd0, 0x7f, 0xed, 0x24, 0x97, 0x0a, 0xe7,
+	0xd8, 0x77, 0x51, 0x7a, 0x66, 0x61, 0x7a, 0x9f, 0xc0, 0xa3, 0x43, 0x8e, 0x70, 0xf3, 0x5b, 0xfd,
+	0x1c, 0xd6, 0xdf, 0x84, 0xee, 0x3d, 0x14, 0x23, 0xd8, 0x64, 0xdb, 0x5a, 0xf8, 0x74, 0xef, 0x79,
+	0xb0, 0x33, 0x47, 0xb0, 0x34, 0xf7, 0x08, 0x9a, 0xb9, 0x23, 0x68, 0xff, 0xc3, 0x80, 0xba, 0x0a,
+	0xe9, 0xca, 0x67, 0x38, 0xc8, 0xdc, 0x89, 0x1a, 0x0e, 0xd2, 0xd0, 0xaa, 0x82, 0xd1, 0x71, 0x19,
+	0x1e, 0xc5, 0x52, 0x51, 0x2d, 0xa5, 0x68, 0xad, 0x37, 0xe6, 0xfc, 0xde, 0x1c, 0xc3, 0x9a, 0x32,
+	0x1a, 0xe8, 0x37, 0x59, 0xf9, 0xce, 0xfb, 0x04, 0x29, 0xbb, 0x83, 0xf4, 0x46, 0xb3, 0x7f, 0x04,
+	0xcd, 0x29, 0x14, 0xa8, 0x2c, 0x92, 0x59, 0x15, 0xfe, 0x1d, 0x3c, 0x29, 0xd4, 0x9e, 0x0b, 0x20,
+	0x3f, 0x83, 0x9a, 0x5a, 0x58, 0x41, 0xc8, 0xe3, 0x7c, 0x72, 0x52, 0x8e, 0xa7, 0x9a, 0xf6, 0x11,
+	0x58, 0x1a, 0x0a, 0x48, 0x79, 0x71, 0x5c, 0xf3, 0x0a, 0x6b, 0x1f, 0xc0, 0x3a, 0x8e, 0x82, 0xe0,
+	0xcc, 0x19, 0x5e, 0xce, 0xdd, 0x3e, 0x73, 0x9d, 0xec, 0x82, 0xd9, 0x77, 0x3c, 0x8e, 0x0a, 0xce,
+	0x48, 0x81, 0x0b, 0xff, 0x9e, 0x26, 0x5d, 0xe2, 0x37, 0xa2, 0x20, 0xec, 0x87, 0xb0, 0xca, 0x2a,
+	0xd5, 0x77, 0x3c, 0x55, 0x4c, 0xfb, 0x05, 0x34, 0xa6, 0x2c, 0x59, 0xb1, 0x2d, 0x09, 0x0f, 0x06,
+	0x2f, 0xcb, 0x72, 0x5a, 0x96, 0xbe, 0xe3, 0x09, 0xb0, 0xb0, 0xbf, 0x80, 0x06, 0x26, 0x6c, 0x21,
+	0xc6, 0x92, 0x91, 0x37, 0xc0, 0xa4, 0x8e, 0x27, 0xa3, 0x60, 0x9f, 0xe8, 0x31, 0x2c, 0x86, 0xe4,
+	0x7a, 0xc0, 0xb8, 0x12, 0xe1, 0x42, 0x72, 0xdd, 0x77, 0x3c, 0xfb, 0xc7, 0xf0, 0x50, 0x33, 0x97,
+	0xab, 0x5a, 0xb0, 0x28, 0xef, 0x74, 0xee, 0xc3, 0xc4, 0x8a, 0xb4, 0x2f, 0x60, 0xad, 0x47, 0x9c,
+	0x78, 0x78, 0x91, 0x3b, 0x3d, 0x6b, 0x50, 0xf9, 0x66, 0x42, 0xe2, 0x1b, 0x05, 0xab, 0x9c, 0xf8,
+	0x4e, 0x67, 0xe7, 0x0f, 0x06, 0xac, 0xe7, 0x96, 0x92, 0xd1, 0xed, 0xc2, 0x62, 0x4c, 0x92, 0x49,
+	0x40, 0x55, 0x59, 0xd6, 0xd3, 0xb2, 0x08, 0x03, 0xcc, 0xa5, 0x58, 0x69, 0x15, 0x5d, 0x3b, 0xa5,
+	0xbb, 0xaf, 0x1d, 0x33, 0x7f, 0xed, 0xfc, 0xde, 0x80, 0x65, 0x7d, 0x81, 0xfb, 0xc3, 0xe5, 0x1a,
+	0x54, 0x92, 0x61, 0x14, 0x8b, 0x1a, 0x18, 0x58, 0x10, 0x68, 0x0f, 0xe0, 0xc2, 0xf7, 0x2e, 0x02,
+	0xdf, 0xbb, 0xa0, 0x89, 0x65, 0xf2, 0x54, 0x50, 0xea, 0xe2, 0x4b, 0x25, 0xc2, 0x9a, 0x96, 0xfd,
+	0x12, 0x6a, 0xa9, 0x80, 0xb9, 0xe5, 0xcf, 0x6a, 0x55, 0x74, 0x4e, 0xa0, 0x0f, 0xa0, 0x76, 0x1e,
+	0x3b, 0x1e, 0xbb, 0xa5, 0xc4, 0x71, 0xaa, 0xe1, 0x29, 0xc3, 0xf6, 0xe0, 0x71, 0xcb, 0xf3, 0x62,
+	0xe2, 0xa5, 0xe0, 0x7f, 0x27, 0x02, 0xbe, 0x80, 0x8d, 0xc4, 0xf7, 0x42, 0xff, 0xdc, 0x1f, 0x3a,
+	0x21, 0x1d, 0x50, 0x12, 0x8f, 0x12, 0xbd, 0xa5, 0x6b, 0x9a, 0xb4, 0xcf, 0x84, 0xbc, 0x5a, 0xff,
+	0x32, 0xc0, 0xba, 0xbd, 0x92, 0x6c, 0x61, 0xb6, 0xd2, 0x62, 0x8f, 0x4d, 0x2b, 0xcd, 0x3a, 0x3c,
+	0x8a, 0x42, 0x7a, 0x11, 0xdc, 0x48, 0x3c, 0x98, 0x76, 0xf8, 0x94, 0xc4, 0x7e, 0xe4, 0xee, 0x4f,
+	0x86, 0x97, 0x84, 0x62, 0xa5, 0x85, 0x9e, 0xcb, 0x63, 0x22, 0x8a, 0xf8, 0x68, 0x7a, 0x4c, 0x48,
+	0x3c, 0x92, 0xba, 0xe2, 0x6a, 0xfd, 0x1c, 0xea, 0x12, 0x7e, 0x03, 0x12, 0x7a, 0xf4, 0x22, 0xb1,
+	0xca, 0xdc, 0x64, 0x2d, 0x35, 0xc1, 0x4e, 0xe8, 0x11, 0x69, 0xb3, 0x22, 0x98, 0xc7, 0x42, 0xd5,
+	0xfe, 0x93, 0x01, 0xcb, 0xfa, 0xfa, 0xe8, 0x33, 0x80, 0x84, 0x3a, 0x31, 0x15, 0x00, 0x6b, 0xdc,
+	0x09, 0xb0, 0x35, 0xae, 0xcd, 0xdf, 0xeb, 0x85, 0xa8, 0x80, 0x7e, 0x05, 0x0f, 0x6f, 0x95, 0x7a,
+	0x5e, 0x52, 0x8d, 0x7c, 0xe9, 0xed, 0x17, 0x00, 0x53, 0x39, 0x03, 0x82, 0x4b, 0xa2, 0x0e, 0x25,
+	0xfb, 0x9c, 0x81, 0x46, 0xaf, 0x60, 0x49, 0xcb, 0x9b, 0xc1, 0xd8, 0x79, 0x1c, 0x8d, 0x64, 0x63,
+	0xf8, 0x37, 0x43, 0x43, 0x1a, 0x49, 0xab, 0x12, 0x8d, 0xa6, 0x8e, 0x4c, 0xdd, 0xd1, 0x4b, 0xd8,
+	0x3c, 0xf2, 0x43, 0x17, 0x93, 0xc0, 0xb9, 0x7d, 0xc5, 0xe6, 0x01, 0x15, 0x41, 0x59, 0xdb, 0x46,
+	0xfc, 0xdb, 0xee, 0x42, 0xb3, 0xc8, 0x81, 0xdc, 0x37, 0x9f, 0xb2, 0xa3, 0x1f, 0x48, 0x60, 0x32,
+	0x33, 0x0f, 0xac, 0x8c, 0x05, 0x56, 0x6a, 0x76, 0x17, 0x56, 0x32, 0x92, 0xef, 0x78, 0x68, 0xed,
+	0x7d, 0x58, 0xeb, 0x4d, 0x3c, 0x8f, 0x24, 0x94, 0x3f, 0xb8, 0xf4, 0xc3, 0x33, 0x8e, 0xc9, 0xb9,
+	0xff, 0x5e, 0x1d, 0x1e, 0x41, 0x15, 0xe6, 0xd8, 0x83, 0xf5, 0x9c, 0x0f, 0x99, 0xde, 0x2f, 0x61,
+	0x29, 0x11, 0x02, 0x7e, 0x17, 0x8a, 0x14, 0xad, 0x69, 0xe3, 0x99, 0x76, 0x2f, 0x55, 0xc0, 0xba,
+	0xb2, 0xfd, 0x0b, 0x58, 0xcd, 0xc9, 0xef, 0xf9, 0x18, 0x6c, 0xc1, 0x3a, 0x26, 0x7e, 0xe8, 0x92,
+	0xf7, 0xb9, 0x76, 0x6d, 0x43, 0x43, 0xce, 0xa3, 0x51, 0xe0, 0x0e, 0xb8, 0x06, 0x77, 0x56, 0xc5,
+	0x75, 0xc1, 0x3f, 0x09, 0xdc, 0x0e, 0xe3, 0xda, 0x7f, 0x2d, 0xc1, 0xaa, 0xf4, 0x71, 0x1a, 0x47,
+	0x5e, 0x4c, 0x92, 0x04, 0xfd, 0x04, 0xca, 0x09, 0x25, 0xa2, 0xca, 0xf5, 0xbd, 0xa7, 0x5a, 0x9f,
+	0x32, 0x7a, 0x3b, 0x3d, 0x4a, 0xc6, 0x98, 0xab, 0xa2, 0x67, 0xb0, 0x9c, 0x44, 0x93, 0x78, 0x48,
+	0xe4, 0x62, 0x22, 0xcc, 0x25, 0xc1, 0xe3, 0x2b, 0x31, 0x15, 0xea, 0xc4, 0x1e, 0xa1, 0x52, 0x45,
+	0x5c, 0x1b, 0x4b, 0x82, 0x27, 0x54, 0x58, 0x96, 0x0c, 0x49, 0xf8, 0x83, 0xc7, 0xc4, 0x82, 0x60,
+	0xfd, 0x19, 0x46, 0x63, 0x9f, 0x88, 0xa9, 0xd0, 0xc4, 0x92, 0xb2, 0xaf, 0xa0, 0xcc, 0x22, 0x40,
+	0x6b, 0xd0, 0xe8, 0xf5, 0xdb, 0xa7, 0x83, 0x37, 0xdd, 0xde, 0x69, 0xfb, 0xa0, 0x73, 0xd4, 0x69,
+	0x1f, 0x36, 0x1e, 0xa0, 0x06, 0x2c, 0x1f, 0xe0, 0x76, 0xab, 0xdf, 0x1e, 0x74, 0xba, 0x87, 0xed,
+	0xb7, 0x0d, 0x03, 0x55, 0xa1, 0x7c, 0x70, 0x72, 0xfa, 0xae, 0x51, 0x42, 0x00, 0x0b, 0x5f, 0xb5,
+	0x71, 0xe7, 0xe8, 0x5d, 0xc3, 0x64, 0x7a, 0xbd, 0xaf, 0x3b, 0xfd, 0x83, 0x2f, 0x07, 0xad, 0xe3,
+	0x4e, 0xab, 0xd7, 0x28, 0x33, 0x7f, 0x87, 0xed, 0xe3, 0x76, 0xbf, 0x3d, 0x38, 0x39, 0x3e, 0x94,
+	0xd6, 0x15, 0x66, 0x7d, 0x78, 0xd2, 0x6d, 0x37, 0x16, 0xec, 0xbf, 0x19, 0xb0, 0xde, 0x9a, 0xd0,
+	0x0b, 0x12, 0x52, 0xf6, 0xd8, 0xf5, 0xa3, 0xf0, 0xc8, 0xf1, 0x83, 0x49, 0x4c, 0xd0, 0x17, 0xb0,
+	0x10, 0x13, 0x27, 0x89, 0x42, 0x59, 0xba, 0x4f, 0xd2, 0xd2, 0x15, 0xea, 0xef, 0x60, 0xae, 0x8c,
+	0xa5, 0x91, 0xfd, 0x0e, 0x16, 0x04, 0x07, 0x6d, 0x00, 0xc2, 0xed, 0x56, 0xef, 0xa4, 0x9b, 0x4b,
+	0xea, 0x21, 0xac, 0xbc, 0xee, 0xf4, 0x7a, 0x9d, 0xee, 0xab, 0x41, 0xff, 0xe4, 0x37, 0xed, 0x6e,
+	0xc3, 0x60, 0xac, 0x4e, 0xf7, 0xab, 0xd6, 0x71, 0xe7, 0x50, 0xb2, 0x4a, 0x8c, 0xd5, 0x7e, 0x7b,
+	0xda, 0xc1, 0x6d, 0xc5, 0x32, 0xed, 0x1f, 0xc2, 0xfa, 0xbe, 0x33, 0xbc, 0x3c, 0xf7, 0x83, 0xe0,
+	0xe4, 0x3a, 0x24, 0x71, 0xba, 0x53, 0x10, 0x94, 0x27, 0x49, 0x7a, 0x6f, 0xf0, 0x6f, 0x7b, 0x0f,
+	0x36, 0xf2, 0xca, 0x77, 0xbd, 0x2e, 0xf6, 0xfe, 0x5d, 0x83, 0x45, 0xb9, 0x09, 0xd1, 0xaf, 0x61,
+	0x49, 0x9b, 0x41, 0xd1, 0x93, 0xb4, 0x0a, 0xb7, 0x87, 0xf2, 0xe6, 0x07, 0xc5, 0x42, 0xb1, 0x9e,
+	0xfd, 0x00, 0x7d, 0x06, 0xb5, 0xf4, 0xa9, 0x88, 0x36, 0x53, 0xe5, 0xfc, 0x10, 0xd9, 0xcc, 0x63,
+	0x81, 0xfd, 0x00, 0xbd, 0x84, 0x65, 0x7d, 0x8c, 0x44, 0xd3, 0xa5, 0x0a, 0xa6, 0xcb, 0x19, 0x0e,
+	0xf4, 0x51, 0x4b, 0x73, 0x50, 0x30, 0x81, 0xcd, 0x70, 0xa0, 0x4f, 0x41, 0x9a, 0x83, 0x82, 0xe1,
+	0xa8, 0xc8, 0xc1, 0x01, 0xd4, 0xb3, 0xf3, 0x11, 0xfa, 0x70, 0x1a, 0x43, 0xd1, 0xe0, 0x54, 0xe4,
+	0xe4, 0xad, 0xf8, 0x37, 0x24, 0x3b, 0x3b, 0x21, 0x3b, 0x53, 0xf8, 0xc2, 0xc1, 0xea, 0xce, 0xe6,
+	0x9c, 0xe9, 0x7f, 0x36, 0xa4, 0x33, 0x03, 0xfa, 0xb8, 0xc0, 0x2c, 0x3f, 0x7f, 0x34, 0xbf, 0x37,
+	0x5f, 0x29, 0x5d, 0xe3, 0xb7, 0xf0, 0xf0, 0xd6, 0xac, 0x80, 0x9e, 0x15, 0x6d, 0x84, 0xcc, 0x1c,
+	0xd1, 0x9c, 0x35, 0x87, 0x88, 0xaa, 0x66, 0xc7, 0x06, 0xad, 0xaa, 0x85, 0xf3, 0x44, 0x51, 0x55,
+	0x5b, 0x50, 0x55, 0x4f, 0x7e, 0x64, 0x65, 0x72, 0xd1, 0x06, 0x83, 0xe6, 0x66, 0x81, 0x24, 0x4d,
+	0xed, 0x10, 0x6a, 0xe9, 0x03, 0x5e, 0xdb, 0xdb, 0xf9, 0x99, 0xa0, 0xd9, 0x2c, 0x12, 0xa5, 0x5e,
+	0x4e, 0x61, 0x25, 0xf3, 0xd8, 0x46, 0x4f, 0x73, 0x6f, 0xea, 0x5c, 0x53, 0x3f, 0x9c, 0x25, 0x4e,
+	0x3d, 0xbe, 0x83, 0x46, 0xfe, 0xf9, 0x87, 0xb6, 0xa6, 0x50, 0x56, 0xfc, 0x06, 0x6d, 0x3e, 0x9b,
+	0xa3, 0x91, 0xba, 0x1e, 0x00, 0xba, 0xfd, 0x46, 0xd0, 0xf6, 0xe2, 0xcc, 0x17, 0x48, 0xf3, 0xe3,
+	0xb9, 0x3a, 0x99, 0x6a, 0xe8, 0x17, 0xb4, 0x5e, 0x8d, 0x82, 0xcb, 0x5f, 0xaf, 0x46, 0xd1, 0xbd,
+	0x6e, 0x3f, 0xd8, 0xfb, 0xbb, 0x01, 0xcb, 0x72, 0x9d, 0x96, 0x3b, 0xf2, 0x43, 0xd4, 0x85, 0x7a,
+	0xf6, 0xd6, 0xd5, 0xb7, 0x4f, 0xd1, 0x75, 0xdc, 0xb4, 0x66, 0x5d, 0xa1, 0xf6, 0x83, 0x4f, 0x0d,
+	0xd4, 0x83, 0x7a, 0x16, 0x6e, 0x35, 0x7f, 0x85, 0xa0, 0xdd, 0xfc, 0x68, 0xa6, 0x5c, 0x45, 0x7d,
+	0xb6, 0xc0, 0xdf, 0xb0, 0x3f, 0xfd, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa2, 0xf3, 0xfa, 0x76,
+	0xf2, 0x17, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// WrapupsClient is the client API for Wrapups service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WrapupsClient interface {
+	// ListWrapups returns the list of wrapup document stored in Elasticsearch.
+	ListWrapups(ctx context.Context, in *ListWrapupsRequest, opts ...grpc.CallOption) (*ListWrapupsResponse, error)
+	// GetWrapup returns a wrapup document matched to request.
+	GetWrapup(ctx context.Context, in *GetWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
+	// CreateWrapup creates new wrapup document and stores it in Elasticsearch.
+	CreateWrapup(ctx context.Context, in *CreateWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
+	// UpdateWrapup updates the fields of existing wrapup document specified by update_mask.
+	UpdateWrapup(ctx context.Context, in *UpdateWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
+	// DeleteWrapup moves a wrapup document to the trash.
+	// Deleted documents are purged permanently after the purge period configured in server.
+	DeleteWrapup(ctx context.Context, in *DeleteWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
+	// UndeleteWrapup restores a wrapup document from the trash.
+	UndeleteWrapup(ctx context.Context, in *UndeleteWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
+	// ListDeletedWrapups returns the list of wrapup document in the trash.
+	ListDeletedWrapups(ctx context.Context, in *ListDeletedWrapupsRequest, opts ...grpc.CallOption) (*ListWrapupsResponse, error)
+	// ListWrapupRevisions returns the list of prior revisions of a wrapup document.
+	ListWrapupRevisions(ctx context.Context, in *ListWrapupRevisionsRequest, opts ...grpc.CallOption) (*ListWrapupRevisionsResponse, error)
+	// GetWrapupRevision returns a prior revision of a wrapup document.
+	GetWrapupRevision(ctx context.Context, in *GetWrapupRevisionRequest, opts ...grpc.CallOption) (*WrapupRevision, error)
+	// RollbackWrapup restores the contents of a wrapup document to the specified revision.
+	// The contents before rollback are also recorded as a new revision.
+	RollbackWrapup(ctx context.Context, in *RollbackWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error)
+	// ListTags returns all tags attached to wrapup documents with the number of documents.
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
+	// RenameTag renames a tag in all wrapup documents.
+	// If the new tag is already used, two tags are merged. Admin privilege is required.
+	RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*RenameTagResponse, error)
+	// SearchWrapups runs full-text search across all text fields of wrapup documents.
+	// Results are ordered by relevance and include highlighted fragments.
+	SearchWrapups(ctx context.Context, in *SearchWrapupsRequest, opts ...grpc.CallOption) (*SearchWrapupsResponse, error)
+	// AggregateWrapups returns the statistics of wrapup documents.
+	AggregateWrapups(ctx context.Context, in *AggregateWrapupsRequest, opts ...grpc.CallOption) (*AggregateWrapupsResponse, error)
+	// FindRelatedWrapups returns wrapup documents which are similar to the given one.
+	FindRelatedWrapups(ctx context.Context, in *FindRelatedWrapupsRequest, opts ...grpc.CallOption) (*FindRelatedWrapupsResponse, error)
+	// SuggestTitles returns wrapup documents whose title starts with the given prefix.
+	SuggestTitles(ctx context.Context, in *SuggestTitlesRequest, opts ...grpc.CallOption) (*SuggestTitlesResponse, error)
+}
+
+type wrapupsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWrapupsClient(cc *grpc.ClientConn) WrapupsClient {
+	return &wrapupsClient{cc}
+}
+
+func (c *wrapupsClient) ListWrapups(ctx context.Context, in *ListWrapupsRequest, opts ...grpc.CallOption) (*ListWrapupsResponse, error) {
+	out := new(ListWrapupsResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/ListWrapups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) GetWrapup(ctx context.Context, in *GetWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
+	out := new(Wrapup)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/GetWrapup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) CreateWrapup(ctx context.Context, in *CreateWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
+	out := new(Wrapup)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/CreateWrapup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) UpdateWrapup(ctx context.Context, in *UpdateWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
+	out := new(Wrapup)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/UpdateWrapup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) DeleteWrapup(ctx context.Context, in *DeleteWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
+	out := new(Wrapup)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/DeleteWrapup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) UndeleteWrapup(ctx context.Context, in *UndeleteWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
+	out := new(Wrapup)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/UndeleteWrapup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) ListDeletedWrapups(ctx context.Context, in *ListDeletedWrapupsRequest, opts ...grpc.CallOption) (*ListWrapupsResponse, error) {
+	out := new(ListWrapupsResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/ListDeletedWrapups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) ListWrapupRevisions(ctx context.Context, in *ListWrapupRevisionsRequest, opts ...grpc.CallOption) (*ListWrapupRevisionsResponse, error) {
+	out := new(ListWrapupRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/ListWrapupRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) GetWrapupRevision(ctx context.Context, in *GetWrapupRevisionRequest, opts ...grpc.CallOption) (*WrapupRevision, error) {
+	out := new(WrapupRevision)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/GetWrapupRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) RollbackWrapup(ctx context.Context, in *RollbackWrapupRequest, opts ...grpc.CallOption) (*Wrapup, error) {
+	out := new(Wrapup)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/RollbackWrapup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error) {
+	out := new(ListTagsResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/ListTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*RenameTagResponse, error) {
+	out := new(RenameTagResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/RenameTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) SearchWrapups(ctx context.Context, in *SearchWrapupsRequest, opts ...grpc.CallOption) (*SearchWrapupsResponse, error) {
+	out := new(SearchWrapupsResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/SearchWrapups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) AggregateWrapups(ctx context.Context, in *AggregateWrapupsRequest, opts ...grpc.CallOption) (*AggregateWrapupsResponse, error) {
+	out := new(AggregateWrapupsResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/AggregateWrapups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) FindRelatedWrapups(ctx context.Context, in *FindRelatedWrapupsRequest, opts ...grpc.CallOption) (*FindRelatedWrapupsResponse, error) {
+	out := new(FindRelatedWrapupsResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/FindRelatedWrapups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wrapupsClient) SuggestTitles(ctx context.Context, in *SuggestTitlesRequest, opts ...grpc.CallOption) (*SuggestTitlesResponse, error) {
+	out := new(SuggestTitlesResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.Wrapups/SuggestTitles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WrapupsServer is the server API for Wrapups service.
+type WrapupsServer interface {
 	// ListWrapups returns the list of wrapup document stored in Elasticsearch.
 	ListWrapups(context.Context, *ListWrapupsRequest) (*ListWrapupsResponse, error)
 	// GetWrapup returns a wrapup document matched to request.
 	GetWrapup(context.Context, *GetWrapupRequest) (*Wrapup, error)
 	// CreateWrapup creates new wrapup document and stores it in Elasticsearch.
 	CreateWrapup(context.Context, *CreateWrapupRequest) (*Wrapup, error)
+	// UpdateWrapup updates the fields of existing wrapup document specified by update_mask.
+	UpdateWrapup(context.Context, *UpdateWrapupRequest) (*Wrapup, error)
+	// DeleteWrapup moves a wrapup document to the trash.
+	// Deleted documents are purged permanently after the purge period configured in server.
+	DeleteWrapup(context.Context, *DeleteWrapupRequest) (*Wrapup, error)
+	// UndeleteWrapup restores a wrapup document from the trash.
+	UndeleteWrapup(context.Context, *UndeleteWrapupRequest) (*Wrapup, error)
+	// ListDeletedWrapups returns the list of wrapup document in the trash.
+	ListDeletedWrapups(context.Context, *ListDeletedWrapupsRequest) (*ListWrapupsResponse, error)
+	// ListWrapupRevisions returns the list of prior revisions of a wrapup document.
+	ListWrapupRevisions(context.Context, *ListWrapupRevisionsRequest) (*ListWrapupRevisionsResponse, error)
+	// GetWrapupRevision returns a prior revision of a wrapup document.
+	GetWrapupRevision(context.Context, *GetWrapupRevisionRequest) (*WrapupRevision, error)
+	// RollbackWrapup restores the contents of a wrapup document to the specified revision.
+	// The contents before rollback are also recorded as a new revision.
+	RollbackWrapup(context.Context, *RollbackWrapupRequest) (*Wrapup, error)
+	// ListTags returns all tags attached to wrapup documents with the number of documents.
+	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+	// RenameTag renames a tag in all wrapup documents.
+	// If the new tag is already used, two tags are merged. Admin privilege is required.
+	RenameTag(context.Context, *RenameTagRequest) (*RenameTagResponse, error)
+	// SearchWrapups runs full-text search across all text fields of wrapup documents.
+	// Results are ordered by relevance and include highlighted fragments.
+	SearchWrapups(context.Context, *SearchWrapupsRequest) (*SearchWrapupsResponse, error)
+	// AggregateWrapups returns the statistics of wrapup documents.
+	AggregateWrapups(context.Context, *AggregateWrapupsRequest) (*AggregateWrapupsResponse, error)
+	// FindRelatedWrapups returns wrapup documents which are similar to the given one.
+	FindRelatedWrapups(context.Context, *FindRelatedWrapupsRequest) (*FindRelatedWrapupsResponse, error)
+	// SuggestTitles returns wrapup documents whose title starts with the given prefix.
+	SuggestTitles(context.Context, *SuggestTitlesRequest) (*SuggestTitlesResponse, error)
+}
+
+func RegisterWrapupsServer(s *grpc.Server, srv WrapupsServer) {
+	s.RegisterService(&_Wrapups_serviceDesc, srv)
+}
+
+func _Wrapups_ListWrapups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWrapupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).ListWrapups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/ListWrapups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).ListWrapups(ctx, req.(*ListWrapupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_GetWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWrapupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).GetWrapup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/GetWrapup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).GetWrapup(ctx, req.(*GetWrapupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_CreateWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWrapupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).CreateWrapup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/CreateWrapup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).CreateWrapup(ctx, req.(*CreateWrapupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterWrapupsServer(s *grpc.Server, srv WrapupsServer) {
-	s.RegisterService(&_Wrapups_serviceDesc, srv)
+func _Wrapups_UpdateWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWrapupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).UpdateWrapup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/UpdateWrapup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).UpdateWrapup(ctx, req.(*UpdateWrapupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Wrapups_ListWrapups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListWrapupsRequest)
+func _Wrapups_DeleteWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWrapupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WrapupsServer).ListWrapups(ctx, in)
+		return srv.(WrapupsServer).DeleteWrapup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wrapups.Wrapups/ListWrapups",
+		FullMethod: "/wrapups.Wrapups/DeleteWrapup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WrapupsServer).ListWrapups(ctx, req.(*ListWrapupsRequest))
+		return srv.(WrapupsServer).DeleteWrapup(ctx, req.(*DeleteWrapupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Wrapups_GetWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetWrapupRequest)
+func _Wrapups_UndeleteWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteWrapupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WrapupsServer).GetWrapup(ctx, in)
+		return srv.(WrapupsServer).UndeleteWrapup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wrapups.Wrapups/GetWrapup",
+		FullMethod: "/wrapups.Wrapups/UndeleteWrapup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WrapupsServer).GetWrapup(ctx, req.(*GetWrapupRequest))
+		return srv.(WrapupsServer).UndeleteWrapup(ctx, req.(*UndeleteWrapupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Wrapups_CreateWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateWrapupRequest)
+func _Wrapups_ListDeletedWrapups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedWrapupsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WrapupsServer).CreateWrapup(ctx, in)
+		return srv.(WrapupsServer).ListDeletedWrapups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wrapups.Wrapups/CreateWrapup",
+		FullMethod: "/wrapups.Wrapups/ListDeletedWrapups",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WrapupsServer).CreateWrapup(ctx, req.(*CreateWrapupRequest))
+		return srv.(WrapupsServer).ListDeletedWrapups(ctx, req.(*ListDeletedWrapupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_ListWrapupRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWrapupRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).ListWrapupRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/ListWrapupRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).ListWrapupRevisions(ctx, req.(*ListWrapupRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_GetWrapupRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWrapupRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).GetWrapupRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/GetWrapupRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).GetWrapupRevision(ctx, req.(*GetWrapupRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_RollbackWrapup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackWrapupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).RollbackWrapup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/RollbackWrapup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).RollbackWrapup(ctx, req.(*RollbackWrapupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/ListTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).ListTags(ctx, req.(*ListTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_RenameTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).RenameTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/RenameTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).RenameTag(ctx, req.(*RenameTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_SearchWrapups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchWrapupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).SearchWrapups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/SearchWrapups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).SearchWrapups(ctx, req.(*SearchWrapupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_AggregateWrapups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateWrapupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).AggregateWrapups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/AggregateWrapups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).AggregateWrapups(ctx, req.(*AggregateWrapupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_FindRelatedWrapups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindRelatedWrapupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).FindRelatedWrapups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/FindRelatedWrapups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).FindRelatedWrapups(ctx, req.(*FindRelatedWrapupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wrapups_SuggestTitles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTitlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsServer).SuggestTitles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.Wrapups/SuggestTitles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsServer).SuggestTitles(ctx, req.(*SuggestTitlesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -490,7 +3228,194 @@ var _Wrapups_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateWrapup",
 			Handler:    _Wrapups_CreateWrapup_Handler,
 		},
+		{
+			MethodName: "UpdateWrapup",
+			Handler:    _Wrapups_UpdateWrapup_Handler,
+		},
+		{
+			MethodName: "DeleteWrapup",
+			Handler:    _Wrapups_DeleteWrapup_Handler,
+		},
+		{
+			MethodName: "UndeleteWrapup",
+			Handler:    _Wrapups_UndeleteWrapup_Handler,
+		},
+		{
+			MethodName: "ListDeletedWrapups",
+			Handler:    _Wrapups_ListDeletedWrapups_Handler,
+		},
+		{
+			MethodName: "ListWrapupRevisions",
+			Handler:    _Wrapups_ListWrapupRevisions_Handler,
+		},
+		{
+			MethodName: "GetWrapupRevision",
+			Handler:    _Wrapups_GetWrapupRevision_Handler,
+		},
+		{
+			MethodName: "RollbackWrapup",
+			Handler:    _Wrapups_RollbackWrapup_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _Wrapups_ListTags_Handler,
+		},
+		{
+			MethodName: "RenameTag",
+			Handler:    _Wrapups_RenameTag_Handler,
+		},
+		{
+			MethodName: "SearchWrapups",
+			Handler:    _Wrapups_SearchWrapups_Handler,
+		},
+		{
+			MethodName: "AggregateWrapups",
+			Handler:    _Wrapups_AggregateWrapups_Handler,
+		},
+		{
+			MethodName: "FindRelatedWrapups",
+			Handler:    _Wrapups_FindRelatedWrapups_Handler,
+		},
+		{
+			MethodName: "SuggestTitles",
+			Handler:    _Wrapups_SuggestTitles_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/wrapups/wrapups.proto",
 }
+
+// WrapupsAdminClient is the client API for WrapupsAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WrapupsAdminClient interface {
+	// ReindexWrapups creates new index with current mapping, copies all wrapup documents to it,
+	// verifies the number of documents and switches the alias to the new index atomically.
+	// Writes through this server are held and writes through other servers fail until the alias is switched.
+	// The progress is streamed.
+	ReindexWrapups(ctx context.Context, in *ReindexWrapupsRequest, opts ...grpc.CallOption) (WrapupsAdmin_ReindexWrapupsClient, error)
+	// BackfillOwners sets the given user as the owner of wrapup objects created before ownership was recorded.
+	BackfillOwners(ctx context.Context, in *BackfillOwnersRequest, opts ...grpc.CallOption) (*BackfillOwnersResponse, error)
+}
+
+type wrapupsAdminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWrapupsAdminClient(cc *grpc.ClientConn) WrapupsAdminClient {
+	return &wrapupsAdminClient{cc}
+}
+
+func (c *wrapupsAdminClient) ReindexWrapups(ctx context.Context, in *ReindexWrapupsRequest, opts ...grpc.CallOption) (WrapupsAdmin_ReindexWrapupsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WrapupsAdmin_serviceDesc.Streams[0], "/wrapups.WrapupsAdmin/ReindexWrapups", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &wrapupsAdminReindexWrapupsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WrapupsAdmin_ReindexWrapupsClient interface {
+	Recv() (*ReindexProgress, error)
+	grpc.ClientStream
+}
+
+type wrapupsAdminReindexWrapupsClient struct {
+	grpc.ClientStream
+}
+
+func (x *wrapupsAdminReindexWrapupsClient) Recv() (*ReindexProgress, error) {
+	m := new(ReindexProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *wrapupsAdminClient) BackfillOwners(ctx context.Context, in *BackfillOwnersRequest, opts ...grpc.CallOption) (*BackfillOwnersResponse, error) {
+	out := new(BackfillOwnersResponse)
+	err := c.cc.Invoke(ctx, "/wrapups.WrapupsAdmin/BackfillOwners", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WrapupsAdminServer is the server API for WrapupsAdmin service.
+type WrapupsAdminServer interface {
+	// ReindexWrapups creates new index with current mapping, copies all wrapup documents to it,
+	// verifies the number of documents and switches the alias to the new index atomically.
+	// Writes through this server are held and writes through other servers fail until the alias is switched.
+	// The progress is streamed.
+	ReindexWrapups(*ReindexWrapupsRequest, WrapupsAdmin_ReindexWrapupsServer) error
+	// BackfillOwners sets the given user as the owner of wrapup objects created before ownership was recorded.
+	BackfillOwners(context.Context, *BackfillOwnersRequest) (*BackfillOwnersResponse, error)
+}
+
+func RegisterWrapupsAdminServer(s *grpc.Server, srv WrapupsAdminServer) {
+	s.RegisterService(&_WrapupsAdmin_serviceDesc, srv)
+}
+
+func _WrapupsAdmin_ReindexWrapups_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReindexWrapupsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WrapupsAdminServer).ReindexWrapups(m, &wrapupsAdminReindexWrapupsServer{stream})
+}
+
+type WrapupsAdmin_ReindexWrapupsServer interface {
+	Send(*ReindexProgress) error
+	grpc.ServerStream
+}
+
+type wrapupsAdminReindexWrapupsServer struct {
+	grpc.ServerStream
+}
+
+func (x *wrapupsAdminReindexWrapupsServer) Send(m *ReindexProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WrapupsAdmin_BackfillOwners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackfillOwnersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WrapupsAdminServer).BackfillOwners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wrapups.WrapupsAdmin/BackfillOwners",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WrapupsAdminServer).BackfillOwners(ctx, req.(*BackfillOwnersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WrapupsAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "wrapups.WrapupsAdmin",
+	HandlerType: (*WrapupsAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BackfillOwners",
+			Handler:    _WrapupsAdmin_BackfillOwners_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReindexWrapups",
+			Handler:       _WrapupsAdmin_ReindexWrapups_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/wrapups/wrapups.proto",
+}
@@ -0,0 +1,70 @@
+package wrapups
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+// TestFileDescriptor checks that the embedded file descriptor is generated from the same proto as the messages,
+// so that reflection and descriptor based tools see all fields.
+func TestFileDescriptor(t *testing.T) {
+	gz := proto.FileDescriptor("pkg/wrapups/wrapups.proto")
+	if gz == nil {
+		t.Fatal("file descriptor is not registered")
+	}
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fd descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(b, &fd); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range fd.MessageType {
+		name := fd.GetPackage() + "." + msg.GetName()
+		typ := proto.MessageType(name)
+		if typ == nil {
+			t.Errorf("message %s is not registered", name)
+			continue
+		}
+		want := make(map[int32]string)
+		for _, field := range msg.Field {
+			want[field.GetNumber()] = field.GetName()
+		}
+		got := make(map[int32]string)
+		st := typ.Elem()
+		for i := 0; i < st.NumField(); i++ {
+			tag := st.Field(i).Tag.Get("protobuf")
+			if tag == "" {
+				continue
+			}
+			// tag is like "bytes,1,opt,name=id,proto3"
+			parts := strings.Split(tag, ",")
+			n, err := strconv.Atoi(parts[1])
+			if err != nil {
+				t.Fatalf("invalid tag of %s.%s: %s", name, st.Field(i).Name, tag)
+			}
+			for _, part := range parts {
+				if strings.HasPrefix(part, "name=") {
+					got[int32(n)] = strings.TrimPrefix(part, "name=")
+				}
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("fields of %s = %v, but descriptor has %v", name, got, want)
+		}
+	}
+}
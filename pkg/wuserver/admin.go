@@ -0,0 +1,73 @@
+package wuserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mas9612/wrapups/pkg/auth"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkAdmin returns PermissionDenied if the user of ctx is not an administrator.
+// Returned error is already converted to gRPC status.
+func (s *WrapupsServer) checkAdmin(ctx context.Context) error {
+	user, _ := auth.UserFromContext(ctx)
+	if !s.admins[user] {
+		s.logger.Warn("admin RPC is called by non-admin user", zap.String("user", user))
+		return status.Error(codes.PermissionDenied, "admin privilege is required")
+	}
+	return nil
+}
+
+// ReindexWrapups creates new index with current mapping, copies all wrapup documents to it,
+// verifies the number of documents and switches the alias to the new index atomically.
+// The progress is sent to the stream at each step.
+func (s *WrapupsServer) ReindexWrapups(req *pb.ReindexWrapupsRequest, stream pb.WrapupsAdmin_ReindexWrapupsServer) error {
+	ctx := stream.Context()
+	if err := s.checkAdmin(ctx); err != nil {
+		return err
+	}
+	reindexer, ok := s.store.(Reindexer)
+	if !ok {
+		return status.Error(codes.Unimplemented, "reindex is not supported by the storage backend")
+	}
+
+	var sendErr error
+	err := reindexer.Reindex(ctx, req.DeleteOldIndex, func(progress *pb.ReindexProgress) {
+		// if the client has gone, ctx is canceled and reindex is aborted before switching the alias
+		if sendErr == nil {
+			sendErr = stream.Send(progress)
+		}
+	})
+	if err != nil {
+		return s.storeError(err, "", "failed to reindex")
+	}
+	if sendErr != nil {
+		s.logger.Warn("failed to send reindex progress", zap.Error(sendErr))
+	}
+	return nil
+}
+
+// BackfillOwners sets the given user to created_by and updated_by of wrapup documents which do not have them.
+func (s *WrapupsServer) BackfillOwners(ctx context.Context, req *pb.BackfillOwnersRequest) (*pb.BackfillOwnersResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.User == "" {
+		errMsg := "User is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	updated, err := s.store.BackfillOwner(ctx, req.User)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to backfill owners")
+	}
+	s.logger.Info(fmt.Sprintf("backfilled owner of %d documents", updated), zap.String("user", req.User))
+	return &pb.BackfillOwnersResponse{
+		Updated: updated,
+	}, nil
+}
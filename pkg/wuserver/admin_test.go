@@ -0,0 +1,80 @@
+package wuserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mas9612/wrapups/pkg/auth"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBackfillOwners(t *testing.T) {
+	store := newMemoryStore()
+	putWrapups(store,
+		&pb.Wrapup{Id: "none", Version: 1},
+		&pb.Wrapup{Id: "creator", CreatedBy: "alice", Version: 1},
+		&pb.Wrapup{Id: "updater", UpdatedBy: "bob", Version: 1},
+		&pb.Wrapup{Id: "both", CreatedBy: "alice", UpdatedBy: "bob", Version: 1},
+	)
+	s := &WrapupsServer{
+		store:      store,
+		logger:     zap.NewNop(),
+		titleLocks: newTitleLocks(),
+		admins:     map[string]bool{"admin": true},
+	}
+	ctx := context.Background()
+	req := &pb.BackfillOwnersRequest{User: "legacy"}
+
+	for _, user := range []string{"", "alice"} {
+		if _, err := s.BackfillOwners(auth.NewContext(ctx, user), req); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("err = %v for user %q, want PermissionDenied", err, user)
+		}
+	}
+	if _, err := s.BackfillOwners(auth.NewContext(ctx, "admin"), &pb.BackfillOwnersRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("err = %v without user, want InvalidArgument", err)
+	}
+	if doc, err := store.Get(ctx, "none"); err != nil || doc.CreatedBy != "" || doc.Version != 1 {
+		t.Fatalf("document is backfilled by rejected requests: %v, %v", doc, err)
+	}
+
+	res, err := s.BackfillOwners(auth.NewContext(ctx, "admin"), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Updated != 3 {
+		t.Errorf("updated = %d, want 3", res.Updated)
+	}
+	tests := []struct {
+		id        string
+		createdBy string
+		updatedBy string
+		version   int64
+	}{
+		{"none", "legacy", "legacy", 2},
+		{"creator", "alice", "legacy", 2},
+		{"updater", "legacy", "bob", 2},
+		{"both", "alice", "bob", 1},
+	}
+	for _, tt := range tests {
+		doc, err := store.Get(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.CreatedBy != tt.createdBy || doc.UpdatedBy != tt.updatedBy || doc.Version != tt.version {
+			t.Errorf("%s: created_by, updated_by, version = %s, %s, %d, want %s, %s, %d",
+				tt.id, doc.CreatedBy, doc.UpdatedBy, doc.Version, tt.createdBy, tt.updatedBy, tt.version)
+		}
+	}
+
+	// documents already backfilled are not touched again
+	res, err = s.BackfillOwners(auth.NewContext(ctx, "admin"), &pb.BackfillOwnersRequest{User: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Updated != 0 {
+		t.Errorf("updated = %d for second backfill, want 0", res.Updated)
+	}
+}
@@ -0,0 +1,43 @@
+package wuserver
+
+import (
+	"context"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultSignificantTermsSize is the number of significant terms per month used when not specified.
+	defaultSignificantTermsSize = 5
+	// maxSignificantTermsSize is the maximum number of significant terms per month.
+	maxSignificantTermsSize = 50
+)
+
+// wrapupLengthRanges is the boundaries of text-length buckets.
+var wrapupLengthRanges = []int64{0, 500, 1000, 2000, 5000}
+
+// AggregateWrapups returns the statistics of wrapup documents.
+// Wrapup documents in the trash are not aggregated.
+func (s *WrapupsServer) AggregateWrapups(ctx context.Context, req *pb.AggregateWrapupsRequest) (*pb.AggregateWrapupsResponse, error) {
+	expr, err := parseFilter(req.Filter)
+	if err != nil {
+		s.logger.Error("invalid filter", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	termsSize := int(req.SignificantTermsSize)
+	if termsSize <= 0 {
+		termsSize = defaultSignificantTermsSize
+	} else if termsSize > maxSignificantTermsSize {
+		termsSize = maxSignificantTermsSize
+	}
+
+	res, err := s.store.Aggregate(ctx, newFilter(expr), termsSize)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to aggregate documents")
+	}
+	return res, nil
+}
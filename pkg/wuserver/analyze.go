@@ -0,0 +1,161 @@
+package wuserver
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+// textToken is a term extracted from text with its byte offsets.
+type textToken struct {
+	term       string
+	start, end int
+}
+
+// analyze splits text into lowercased terms.
+// This approximates the standard analyzer of Elasticsearch
+// so that backends other than Elasticsearch match text in the same way.
+func analyze(text string) []textToken {
+	tokens := make([]textToken, 0, len(text)/5)
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, textToken{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, textToken{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// analyzeTerms returns only the terms of analyze.
+func analyzeTerms(text string) []string {
+	tokens := analyze(text)
+	terms := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		terms = append(terms, t.term)
+	}
+	return terms
+}
+
+// containsPhrase reports whether terms contains phrase as consecutive terms.
+func containsPhrase(terms, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(terms); i++ {
+		matched := true
+		for j := range phrase {
+			if terms[i+j] != phrase[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightText returns at most highlightFragments fragments of text around the terms.
+// The terms are enclosed with <em> and </em> like Elasticsearch highlighter.
+func highlightText(text string, terms map[string]bool) []string {
+	var fragments []string
+	tokens := analyze(text)
+	for i := 0; i < len(tokens) && len(fragments) < highlightFragments; {
+		if !terms[tokens[i].term] {
+			i++
+			continue
+		}
+		// the fragment starts at the matched term and extends to highlightFragmentSize bytes
+		start := tokens[i].start
+		end := start + highlightFragmentSize
+		if end < tokens[i].end {
+			end = tokens[i].end
+		}
+		if end >= len(text) {
+			end = len(text)
+		} else {
+			for end > start && !utf8.RuneStart(text[end]) {
+				end--
+			}
+		}
+		var b strings.Builder
+		last := start
+		for ; i < len(tokens) && tokens[i].end <= end; i++ {
+			if !terms[tokens[i].term] {
+				continue
+			}
+			b.WriteString(text[last:tokens[i].start])
+			b.WriteString("<em>")
+			b.WriteString(text[tokens[i].start:tokens[i].end])
+			b.WriteString("</em>")
+			last = tokens[i].end
+		}
+		b.WriteString(text[last:end])
+		fragments = append(fragments, b.String())
+	}
+	return fragments
+}
+
+// keywordValues returns the values of keyword field specified by key like "tags".
+// Empty values are not returned because they are not stored.
+func keywordValues(wrapup *pb.Wrapup, key string) []string {
+	switch key {
+	case "tags":
+		return wrapup.Tags
+	case "created_by":
+		if wrapup.CreatedBy != "" {
+			return []string{wrapup.CreatedBy}
+		}
+	case "updated_by":
+		if wrapup.UpdatedBy != "" {
+			return []string{wrapup.UpdatedBy}
+		}
+	}
+	return nil
+}
+
+// textValues returns the values of text field specified by key like "title" or "metadata.authors".
+func textValues(wrapup *pb.Wrapup, key string) []string {
+	switch key {
+	case "title":
+		return []string{wrapup.Title}
+	case "wrapup":
+		return []string{wrapup.Wrapup}
+	case "comment":
+		return []string{wrapup.Comment}
+	case "note":
+		return []string{wrapup.Note}
+	}
+	m := wrapup.Metadata
+	if m == nil {
+		return nil
+	}
+	switch key {
+	case "metadata.authors":
+		return m.Authors
+	case "metadata.venue":
+		return []string{m.Venue}
+	case "metadata.abstract":
+		return []string{m.Abstract}
+	case "metadata.doi":
+		return []string{m.Doi}
+	case "metadata.arxiv_id":
+		return []string{m.ArxivId}
+	case "metadata.url":
+		return []string{m.Url}
+	}
+	return nil
+}
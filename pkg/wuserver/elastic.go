@@ -0,0 +1,408 @@
+package wuserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/olivere/elastic"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultIndexName is the alias of wrapup index.
+	defaultIndexName = "wrapups"
+	typ              = "_doc"
+
+	// purgeBatchSize is the maximum number of documents purged at once.
+	purgeBatchSize = 1000
+)
+
+// esOrderKeys maps the field names which can be used in order_by to the sort keys of Elasticsearch.
+var esOrderKeys = map[string][]string{
+	"create_time": {"create_time.seconds", "create_time.nanos"},
+	"title":       {"title.keyword"},
+	"relevance":   {"_score"},
+}
+
+// elasticStore is the Store backed by Elasticsearch.
+type elasticStore struct {
+	client        *elastic.Client
+	logger        *zap.Logger
+	index         string
+	revisionIndex string
+
+	// writeMu is held for reading while writing wrapup documents, and for writing while reindexing,
+	// so that no write to old index is lost by switching index.
+	writeMu sync.RWMutex
+}
+
+// elasticDoc is the document stored in wrapup index.
+type elasticDoc struct {
+	*pb.Wrapup
+	// NormalizedTitle is used to find duplicated titles.
+	NormalizedTitle string `json:"normalized_title"`
+}
+
+// newElasticDoc converts wrapup to the document stored in wrapup index.
+func newElasticDoc(wrapup *pb.Wrapup) *elasticDoc {
+	// ID is stored in the source as well as _id to sort by it.
+	// version is managed as _version, not in the source.
+	doc := *wrapup
+	doc.Version = 0
+	return &elasticDoc{
+		Wrapup:          &doc,
+		NormalizedTitle: normalizeTitle(wrapup.Title),
+	}
+}
+
+// newElasticStore creates and returns new elasticStore.
+// This method also create indices if necessary.
+// If Elasticsearch is unavailable, this method retries with exponential backoff until the startup timeout.
+func newElasticStore(logger *zap.Logger, c *config) (*elasticStore, error) {
+	logger.Info("initializing Elasticsearch client")
+	options, err := esClientOptions(logger, c)
+	if err != nil {
+		errMsg := "failed to initialize Elasticsearch client"
+		logger.Error(errMsg, zap.Error(err))
+		return nil, errors.Wrap(err, errMsg)
+	}
+
+	// Elasticsearch may be still starting, e.g. when started with wuserver at the same time
+	deadline := time.Now().Add(c.startupTimeout)
+	wait := retryInitialWait
+	for {
+		store, err := connectElasticStore(logger, options)
+		if err == nil {
+			return store, nil
+		}
+		if !isUnavailable(err) || time.Now().Add(wait).After(deadline) {
+			logger.Error("failed to connect to Elasticsearch", zap.Error(err))
+			return nil, err
+		}
+		logger.Warn("Elasticsearch is unavailable. retrying", zap.Duration("wait", wait), zap.Error(err))
+		time.Sleep(wait)
+		wait *= 2
+		if wait > startupMaxWait {
+			wait = startupMaxWait
+		}
+	}
+}
+
+// connectElasticStore creates Elasticsearch client and prepares indices.
+func connectElasticStore(logger *zap.Logger, options []elastic.ClientOptionFunc) (*elasticStore, error) {
+	client, err := elastic.NewClient(options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize Elasticsearch client")
+	}
+	if err := migrateIndex(context.Background(), client, logger, defaultIndexName); err != nil {
+		client.Stop()
+		return nil, errors.Wrap(err, "failed to migrate index")
+	}
+	if err := createIndexIfNotExists(client, logger, revisionIndexName, revisionIndexBody); err != nil {
+		client.Stop()
+		return nil, err
+	}
+
+	return &elasticStore{
+		client:        client,
+		logger:        logger,
+		index:         defaultIndexName,
+		revisionIndex: revisionIndexName,
+	}, nil
+}
+
+// createIndexIfNotExists creates new index with given body if it does not exist yet.
+// If body is empty, the index is created with default settings.
+func createIndexIfNotExists(client *elastic.Client, logger *zap.Logger, index, body string) error {
+	exists, err := client.IndexExists(index).Do(context.Background())
+	if err != nil {
+		errMsg := "failed to check whether index exists"
+		logger.Error(errMsg, zap.Error(err))
+		return errors.Wrap(err, errMsg)
+	}
+	if exists {
+		return nil
+	}
+
+	logger.Info(fmt.Sprintf("index \"%s\" not found. creating", index))
+	service := client.CreateIndex(index)
+	if body != "" {
+		service = service.BodyString(body)
+	}
+	if _, err := service.Do(context.Background()); err != nil {
+		errMsg := fmt.Sprintf("failed to create index \"%s\"", index)
+		logger.Error(errMsg, zap.Error(err))
+		return errors.Wrap(err, errMsg)
+	}
+	return nil
+}
+
+// decodeWrapup decodes the source of wrapup document.
+// version is the _version of the document, which is nil if not requested.
+func decodeWrapup(id string, version *int64, source *json.RawMessage) (*pb.Wrapup, error) {
+	wrapup := &pb.Wrapup{}
+	if err := json.Unmarshal(*source, wrapup); err != nil {
+		return nil, errors.Wrap(err, "failed to Unmarshal response to JSON")
+	}
+	wrapup.Id = id
+	if version != nil {
+		wrapup.Version = *version
+	}
+	return wrapup, nil
+}
+
+// Create stores new wrapup document and returns it with the assigned ID.
+func (s *elasticStore) Create(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	// ID is generated here instead of Elasticsearch because it is also stored in the source
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	created := *wrapup
+	created.Id = id
+	// wait for refresh so that the duplicate check of the next request finds this document
+	res, err := s.client.Index().Index(s.index).Type(typ).Id(id).OpType("create").Refresh("wait_for").
+		BodyJson(newElasticDoc(&created)).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new document")
+	}
+	created.Version = res.Version
+	return &created, nil
+}
+
+// Get returns the wrapup document which has given ID, including the one in the trash.
+func (s *elasticStore) Get(ctx context.Context, id string) (*pb.Wrapup, error) {
+	result, err := s.client.Get().Index(s.index).Id(id).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get document from Elasticsearch")
+	}
+	return decodeWrapup(result.Id, result.Version, result.Source)
+}
+
+// Update replaces the wrapup document which has the same ID with given one.
+func (s *elasticStore) Update(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	// the document is replaced only if it has not been changed since it was read
+	res, err := s.client.Index().Index(s.index).Type(typ).Id(wrapup.Id).Version(wrapup.Version).
+		BodyJson(newElasticDoc(wrapup)).Do(ctx)
+	if err != nil {
+		if elastic.IsConflict(err) {
+			return nil, ErrConflict
+		}
+		return nil, errors.Wrap(err, "failed to update document")
+	}
+	updated := *wrapup
+	updated.Version = res.Version
+	return &updated, nil
+}
+
+// List returns the wrapup documents matched to query.
+func (s *elasticStore) List(ctx context.Context, q *ListQuery) (*pb.ListWrapupsResponse, error) {
+	query := elastic.NewBoolQuery()
+	if q.Deleted {
+		query = query.Filter(elastic.NewExistsQuery("delete_time"))
+	} else {
+		query = query.MustNot(elastic.NewExistsQuery("delete_time"))
+	}
+	if q.Filter == nil {
+		query = query.Must(elastic.NewMatchAllQuery())
+	} else {
+		query = query.Must(esFilterQuery(q.Filter.expr))
+	}
+	sorters := esSorters(q.OrderBy)
+	// one more document is fetched to know whether the next page exists
+	search := s.client.Search(s.index).Query(query).Version(true).Size(q.PageSize + 1).SortBy(sorters...)
+	if q.PageToken != "" {
+		searchAfter, err := decodePageToken(q.PageToken)
+		if err != nil || len(searchAfter) != len(sorters) {
+			return nil, ErrInvalidPageToken
+		}
+		search = search.SearchAfter(searchAfter...)
+	}
+	result, err := search.Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get documents from Elasticsearch")
+	}
+
+	hits, nextPageToken, err := esPage(result.Hits.Hits, q.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	wrapups := make([]*pb.Wrapup, 0, len(hits))
+	for _, hit := range hits {
+		wrapup, err := decodeWrapup(hit.Id, hit.Version, hit.Source)
+		if err != nil {
+			return nil, err
+		}
+		wrapups = append(wrapups, wrapup)
+	}
+
+	return &pb.ListWrapupsResponse{
+		Count:         int32(result.TotalHits()),
+		Wrapups:       wrapups,
+		NextPageToken: nextPageToken,
+		TotalSize:     int32(result.TotalHits()),
+	}, nil
+}
+
+// Purge removes the wrapup documents deleted before deadline and their revisions permanently.
+// At most purgeBatchSize documents are removed at once.
+func (s *elasticStore) Purge(ctx context.Context, deadline time.Time) (int64, error) {
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	query := elastic.NewRangeQuery("delete_time.seconds").Lte(deadline.Unix())
+	result, err := s.client.Search(s.index).Query(query).FetchSource(false).Size(purgeBatchSize).Do(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to search deleted documents")
+	}
+	if len(result.Hits.Hits) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, 0, len(result.Hits.Hits))
+	wrapupIDs := make([]interface{}, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		ids = append(ids, hit.Id)
+		wrapupIDs = append(wrapupIDs, hit.Id)
+	}
+
+	if _, err := s.client.DeleteByQuery(s.revisionIndex).Query(elastic.NewTermsQuery("wrapup_id", wrapupIDs...)).
+		ProceedOnVersionConflict().Do(ctx); err != nil {
+		return 0, errors.Wrap(err, "failed to delete revisions")
+	}
+	res, err := s.client.DeleteByQuery(s.index).Query(elastic.NewIdsQuery(typ).Ids(ids...)).
+		ProceedOnVersionConflict().Do(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete documents")
+	}
+	return res.Deleted, nil
+}
+
+// FindByTitle returns the wrapup document not in the trash whose normalized title is the same as given one.
+func (s *elasticStore) FindByTitle(ctx context.Context, title string) (*pb.Wrapup, error) {
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewTermQuery("normalized_title", normalizeTitle(title))).
+		MustNot(elastic.NewExistsQuery("delete_time"))
+	result, err := s.client.Search(s.index).Query(query).Version(true).Size(1).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search documents in Elasticsearch")
+	}
+	if len(result.Hits.Hits) == 0 {
+		return nil, ErrNotFound
+	}
+	hit := result.Hits.Hits[0]
+	return decodeWrapup(hit.Id, hit.Version, hit.Source)
+}
+
+// esPage returns the first size hits and the page token of the next page.
+// hits must be fetched with size+1 so that the next page is known to exist.
+// The page token is empty if there is no next page.
+func esPage(hits []*elastic.SearchHit, size int) ([]*elastic.SearchHit, string, error) {
+	if len(hits) <= size {
+		return hits, "", nil
+	}
+	hits = hits[:size]
+	if size == 0 {
+		return hits, "", nil
+	}
+	token, err := encodePageToken(hits[len(hits)-1].Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	return hits, token, nil
+}
+
+// esSorters converts orders to the sorters of Elasticsearch.
+// id is always appended as tiebreaker to make pagination stable.
+func esSorters(orders []Order) []elastic.Sorter {
+	sorters := make([]elastic.Sorter, 0, len(orders)+2)
+	for _, order := range orders {
+		for _, key := range esOrderKeys[order.Field] {
+			if key == "_score" {
+				sorters = append(sorters, elastic.NewScoreSort().Order(!order.Descending))
+			} else {
+				sorters = append(sorters, elastic.NewFieldSort(key).Order(!order.Descending))
+			}
+		}
+	}
+	sorters = append(sorters, elastic.NewFieldSort("id"))
+	return sorters
+}
+
+// esFilterQuery converts parsed filter expression to Elasticsearch query.
+func esFilterQuery(expr filterExpr) elastic.Query {
+	switch e := expr.(type) {
+	case *andExpr:
+		query := elastic.NewBoolQuery()
+		for _, expr := range e.exprs {
+			query = query.Must(esFilterQuery(expr))
+		}
+		return query
+	case *orExpr:
+		query := elastic.NewBoolQuery().MinimumNumberShouldMatch(1)
+		for _, expr := range e.exprs {
+			query = query.Should(esFilterQuery(expr))
+		}
+		return query
+	case *notExpr:
+		return elastic.NewBoolQuery().MustNot(esFilterQuery(e.expr))
+	case *compareExpr:
+		query := esCompareQuery(e)
+		if e.op == "!=" {
+			return elastic.NewBoolQuery().MustNot(query)
+		}
+		return query
+	}
+	return elastic.NewMatchAllQuery()
+}
+
+// esCompareQuery converts comparison to Elasticsearch query.
+// Operator "!=" is treated as "=" here and negated by the caller.
+func esCompareQuery(e *compareExpr) elastic.Query {
+	switch e.field.kind {
+	case textField:
+		if e.phrase {
+			return elastic.NewMatchPhraseQuery(e.field.key, e.text)
+		}
+		return elastic.NewMatchQuery(e.field.key, e.text).Operator("and")
+	case keywordField:
+		return elastic.NewTermQuery(e.field.key, e.text)
+	case numberField:
+		query := elastic.NewRangeQuery(e.field.key)
+		switch e.op {
+		case "<":
+			return query.Lt(e.number)
+		case "<=":
+			return query.Lte(e.number)
+		case ">":
+			return query.Gt(e.number)
+		case ">=":
+			return query.Gte(e.number)
+		}
+		return elastic.NewTermQuery(e.field.key, e.number)
+	case dateField:
+		query := elastic.NewRangeQuery(e.field.key + ".seconds")
+		switch e.op {
+		case "<":
+			return query.Lt(e.from.Unix())
+		case "<=":
+			return query.Lt(e.to.Unix())
+		case ">":
+			return query.Gte(e.to.Unix())
+		case ">=":
+			return query.Gte(e.from.Unix())
+		}
+		return query.Gte(e.from.Unix()).Lt(e.to.Unix())
+	}
+	return elastic.NewMatchAllQuery()
+}
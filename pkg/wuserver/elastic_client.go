@@ -0,0 +1,301 @@
+package wuserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	debuglogger "github.com/mas9612/wrapups/pkg/logger"
+	"github.com/olivere/elastic"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// retryInitialWait is the first wait before retrying the request to Elasticsearch.
+	retryInitialWait = 100 * time.Millisecond
+	// startupMaxWait is the maximum wait between attempts to connect to Elasticsearch on startup.
+	startupMaxWait = 10 * time.Second
+)
+
+// transientStatuses is the HTTP statuses which mean Elasticsearch is temporarily unavailable.
+var transientStatuses = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// esClientOptions validates the connection options in c and returns the options of Elasticsearch client.
+func esClientOptions(logger *zap.Logger, c *config) ([]elastic.ClientOptionFunc, error) {
+	urls, err := esURLs(c)
+	if err != nil {
+		return nil, err
+	}
+	secure := urls[0].Scheme == "https"
+
+	options := make([]elastic.ClientOptionFunc, 0, 10)
+	nodes := make([]string, 0, len(urls))
+	for _, u := range urls {
+		nodes = append(nodes, u.String())
+	}
+	options = append(options, elastic.SetURL(nodes...))
+
+	if c.username != "" && c.apiKey != "" {
+		return nil, errors.New("basic auth and API key cannot be used together")
+	}
+	if c.username == "" && c.password != "" {
+		return nil, errors.New("username is required for basic auth")
+	}
+	if (c.username != "" || c.apiKey != "") && !secure {
+		logger.Warn("credentials are sent to Elasticsearch without TLS")
+	}
+	if c.username != "" {
+		options = append(options, elastic.SetBasicAuth(c.username, c.password))
+	}
+
+	tlsConfig, err := esTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil && !secure {
+		return nil, errors.New("TLS options require https urls of Elasticsearch")
+	}
+	var transport http.RoundTripper = newESTransport(tlsConfig)
+	if c.apiKey != "" {
+		transport = &apiKeyTransport{
+			apiKey: c.apiKey,
+			base:   transport,
+		}
+	}
+	transport = &transientStatusTransport{base: transport}
+	options = append(options, elastic.SetHttpClient(&http.Client{Transport: transport}))
+	if c.retryMaxWait > 0 {
+		options = append(options, elastic.SetRetrier(&esRetrier{
+			backoff: elastic.NewExponentialBackoff(retryInitialWait, c.retryMaxWait),
+		}))
+	}
+
+	// sniffed nodes are accessed with the same scheme
+	options = append(options, elastic.SetSniff(c.sniff), elastic.SetScheme(urls[0].Scheme))
+	if c.sniffInterval > 0 {
+		options = append(options, elastic.SetSnifferInterval(c.sniffInterval))
+	}
+	options = append(options, elastic.SetHealthcheck(c.healthcheck))
+	if c.healthcheckInterval > 0 {
+		options = append(options, elastic.SetHealthcheckInterval(c.healthcheckInterval))
+	}
+
+	if c.trace {
+		l := &debuglogger.Logger{
+			Logger: logger,
+		}
+		options = append(options, elastic.SetTraceLog(l))
+	}
+	return options, nil
+}
+
+// esURLs returns the validated urls of Elasticsearch nodes.
+func esURLs(c *config) ([]*url.URL, error) {
+	rawURLs := c.urls
+	if len(rawURLs) == 0 {
+		rawURLs = []string{fmt.Sprintf("%s://%s:%d", c.scheme, c.url, c.port)}
+	}
+	urls := make([]*url.URL, 0, len(rawURLs))
+	for _, rawURL := range rawURLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid Elasticsearch url \"%s\"", rawURL)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, errors.Errorf("invalid Elasticsearch url \"%s\": scheme must be http or https", rawURL)
+		}
+		// Host is not empty for the url without hostname like "http://:9200"
+		if u.Hostname() == "" {
+			return nil, errors.Errorf("invalid Elasticsearch url \"%s\": host is required", rawURL)
+		}
+		if u.User != nil {
+			// do not include url in the message not to leak the password
+			return nil, errors.New("invalid Elasticsearch url: use basic auth option instead of credentials in url")
+		}
+		urls = append(urls, u)
+	}
+	for _, u := range urls[1:] {
+		if u.Scheme != urls[0].Scheme {
+			return nil, errors.New("all Elasticsearch urls must have the same scheme")
+		}
+	}
+	return urls, nil
+}
+
+// esTLSConfig returns TLS config of the connection to Elasticsearch.
+// nil is returned if no TLS option is set.
+func esTLSConfig(c *config) (*tls.Config, error) {
+	if c.caFile == "" && c.certFile == "" && c.keyFile == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{}
+	if c.caFile != "" {
+		b, err := ioutil.ReadFile(c.caFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CA certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errors.Errorf("no valid certificate found in \"%s\"", c.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if (c.certFile == "") != (c.keyFile == "") {
+		return nil, errors.New("both client certificate and key are required")
+	}
+	if c.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// newESTransport returns the transport with the same settings as http.DefaultTransport except TLS config.
+func newESTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+// apiKeyTransport adds API key to the requests to Elasticsearch.
+type apiKeyTransport struct {
+	apiKey string
+	base   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper must not modify the given request
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Set("Authorization", "ApiKey "+t.apiKey)
+	return t.base.RoundTrip(r)
+}
+
+// transientStatusError is returned by transientStatusTransport instead of the response of transient status.
+type transientStatusError struct {
+	status int
+}
+
+func (e *transientStatusError) Error() string {
+	return fmt.Sprintf("Elasticsearch is unavailable: %d %s", e.status, http.StatusText(e.status))
+}
+
+// transientStatusTransport converts the responses of transient statuses to errors,
+// because Elasticsearch client calls Retrier only when the request fails in transport.
+type transientStatusTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transientStatusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil || !transientStatuses[res.StatusCode] {
+		return res, err
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+	return nil, &transientStatusError{status: res.StatusCode}
+}
+
+// esRetrier retries the requests to Elasticsearch failed by temporary unavailability with backoff.
+type esRetrier struct {
+	backoff elastic.Backoff
+}
+
+// Retry implements elastic.Retrier.
+func (r *esRetrier) Retry(ctx context.Context, retry int, req *http.Request, res *http.Response, err error) (time.Duration, bool, error) {
+	if ctx.Err() != nil || !retryable(req) {
+		return 0, false, nil
+	}
+	wait, ok := r.backoff.Next(retry)
+	return wait, ok, nil
+}
+
+// readOnlyEndpoints is the last path segment of the APIs which only read documents though sent by POST.
+var readOnlyEndpoints = map[string]bool{
+	"_search":  true,
+	"_msearch": true,
+	"_count":   true,
+	"_mget":    true,
+	"_explain": true,
+}
+
+// retryable reports whether the failed request can be retried safely.
+// The request which is not sent because no node is available can always be retried.
+// Otherwise, the request may have been processed even if it failed with transient status or network error,
+// e.g. the response is lost or the proxy in front of Elasticsearch returns the error after forwarding it,
+// so only idempotent requests are retried.
+// Other statuses like 409 are returned as responses by transport, so they never reach here.
+func retryable(req *http.Request) bool {
+	if req == nil {
+		// no node is available
+		return true
+	}
+	return idempotent(req)
+}
+
+// idempotent reports whether req does not change anything even if it is processed more than once.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		path := strings.TrimSuffix(req.URL.Path, "/")
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			path = path[i+1:]
+		}
+		return readOnlyEndpoints[path]
+	}
+	return false
+}
+
+// isUnavailable reports whether err is caused by temporary unavailability of the storage backend.
+func isUnavailable(err error) bool {
+	err = errors.Cause(err)
+	if err == ErrUnavailable || elastic.IsConnErr(err) {
+		return true
+	}
+	if e, ok := err.(*url.Error); ok {
+		err = e.Err
+	}
+	switch e := err.(type) {
+	case *transientStatusError:
+		return true
+	case *elastic.Error:
+		return transientStatuses[e.Status]
+	case net.Error:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package wuserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/olivere/elastic"
+	"github.com/pkg/errors"
+)
+
+const revisionIndexName = "wrapups-revisions"
+
+// revisionIndexBody is the settings of revision index.
+// wrapup_id must be keyword to look up revisions by exact ID.
+const revisionIndexBody = `{
+	"mappings": {
+		"_doc": {
+			"properties": {
+				"wrapup_id": {"type": "keyword"},
+				"revision": {"type": "integer"}
+			}
+		}
+	}
+}`
+
+// SaveRevision stores the given contents of wrapup document as its revision numbered by its version.
+func (s *elasticStore) SaveRevision(ctx context.Context, wrapup *pb.Wrapup) error {
+	// the number is taken from the version instead of counting revisions,
+	// because search does not find the revision saved just before until refreshed
+	revision := &pb.WrapupRevision{
+		WrapupId:           wrapup.Id,
+		Revision:           int32(wrapup.Version),
+		Wrapup:             wrapup,
+		RevisionCreateTime: ptypes.TimestampNow(),
+	}
+	// OpType "create" makes saving the same revision twice conflict instead of overwriting it.
+	_, err := s.client.Index().Index(s.revisionIndex).Type(typ).Id(revisionID(wrapup.Id, revision.Revision)).
+		OpType("create").Refresh("wait_for").BodyJson(revision).Do(ctx)
+	if err != nil {
+		if elastic.IsConflict(err) {
+			return ErrConflict
+		}
+		return errors.Wrap(err, "failed to save revision")
+	}
+	return nil
+}
+
+// ListRevisions returns all revisions of the wrapup document ordered by revision number.
+// At most maxRevisions revisions are returned.
+func (s *elasticStore) ListRevisions(ctx context.Context, id string) ([]*pb.WrapupRevision, error) {
+	query := elastic.NewTermQuery("wrapup_id", id)
+	result, err := s.client.Search(s.revisionIndex).Query(query).Sort("revision", true).Size(maxRevisions).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get revisions from Elasticsearch")
+	}
+
+	revisions := make([]*pb.WrapupRevision, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		var revision pb.WrapupRevision
+		if err := json.Unmarshal(*hit.Source, &revision); err != nil {
+			return nil, errors.Wrap(err, "failed to Unmarshal response to JSON")
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, nil
+}
+
+// GetRevision returns the revision of the wrapup document.
+func (s *elasticStore) GetRevision(ctx context.Context, id string, revision int32) (*pb.WrapupRevision, error) {
+	result, err := s.client.Get().Index(s.revisionIndex).Id(revisionID(id, revision)).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get revision from Elasticsearch")
+	}
+
+	doc := &pb.WrapupRevision{}
+	if err := json.Unmarshal(*result.Source, doc); err != nil {
+		return nil, errors.Wrap(err, "failed to Unmarshal response to JSON")
+	}
+	return doc, nil
+}
+
+// revisionID returns the document ID of revision in revision index.
+func revisionID(id string, revision int32) string {
+	return fmt.Sprintf("%s-%d", id, revision)
+}
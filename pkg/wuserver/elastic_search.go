@@ -0,0 +1,327 @@
+package wuserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/olivere/elastic"
+	"github.com/pkg/errors"
+)
+
+const (
+	// createTimeMillisScript converts create_time to epoch milliseconds for date_histogram.
+	createTimeMillisScript = "doc['create_time.seconds'].value * 1000L"
+	// wrapupLengthScript returns the number of characters of wrapup text.
+	wrapupLengthScript = "params._source.wrapup == null ? 0 : params._source.wrapup.length()"
+
+	// renameTagScript replaces tag params.from with params.to and removes duplicated tags.
+	// updated_by and update_time are set to params.user and the time in params, and _version is incremented by update.
+	renameTagScript = `
+def tags = new ArrayList();
+for (tag in ctx._source.tags) {
+	def name = tag == params.from ? params.to : tag;
+	if (!tags.contains(name)) {
+		tags.add(name);
+	}
+}
+ctx._source.tags = tags;
+ctx._source.updated_by = params.user;
+ctx._source.update_time = ['seconds': params.seconds, 'nanos': params.nanos];
+`
+
+	// backfillOwnerScript sets params.user to created_by and updated_by if they are missing.
+	backfillOwnerScript = `
+if (ctx._source.created_by == null) {
+	ctx._source.created_by = params.user;
+}
+if (ctx._source.updated_by == null) {
+	ctx._source.updated_by = params.user;
+}
+`
+)
+
+// Search runs full-text search across all text fields of wrapup documents not in the trash.
+// Results are ordered by relevance and include highlighted fragments.
+func (s *elasticStore) Search(ctx context.Context, q *SearchQuery) (*pb.SearchWrapupsResponse, error) {
+	match := elastic.NewMultiMatchQuery(q.Query).Type("best_fields")
+	highlight := elastic.NewHighlight().FragmentSize(highlightFragmentSize).NumOfFragments(highlightFragments)
+	for field, boost := range searchFields {
+		match = match.FieldWithBoost(field, boost)
+		highlight = highlight.Fields(elastic.NewHighlighterField(field))
+	}
+	query := elastic.NewBoolQuery().Must(match).MustNot(elastic.NewExistsQuery("delete_time"))
+
+	sorters := []elastic.Sorter{elastic.NewScoreSort(), elastic.NewFieldSort("id")}
+	// one more document is fetched to know whether the next page exists
+	search := s.client.Search(s.index).Query(query).Version(true).Highlight(highlight).Size(q.PageSize + 1).SortBy(sorters...)
+	if q.PageToken != "" {
+		searchAfter, err := decodePageToken(q.PageToken)
+		if err != nil || len(searchAfter) != len(sorters) {
+			return nil, ErrInvalidPageToken
+		}
+		search = search.SearchAfter(searchAfter...)
+	}
+	result, err := search.Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search documents in Elasticsearch")
+	}
+
+	hits, nextPageToken, err := esPage(result.Hits.Hits, q.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*pb.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		wrapup, err := decodeWrapup(hit.Id, hit.Version, hit.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		res := &pb.SearchResult{
+			Wrapup: wrapup,
+		}
+		// _score of hit is not returned when sorted explicitly, so take it from sort values
+		if len(hit.Sort) > 0 {
+			if score, ok := hit.Sort[0].(float64); ok {
+				res.Score = score
+			}
+		}
+		if hit.Score != nil {
+			res.Score = *hit.Score
+		}
+		res.Highlights = esHighlights(hit.Highlight)
+		results = append(results, res)
+	}
+
+	return &pb.SearchWrapupsResponse{
+		Results:       results,
+		NextPageToken: nextPageToken,
+		TotalSize:     int32(result.TotalHits()),
+	}, nil
+}
+
+// esHighlights converts highlight information of search hit ordered by the boost of field.
+func esHighlights(hl elastic.SearchHitHighlight) []*pb.Highlight {
+	fields := make([]string, 0, len(hl))
+	for field := range hl {
+		fields = append(fields, field)
+	}
+	sortHighlightFields(fields)
+
+	results := make([]*pb.Highlight, 0, len(fields))
+	for _, field := range fields {
+		results = append(results, &pb.Highlight{
+			Field:     field,
+			Fragments: hl[field],
+		})
+	}
+	return results
+}
+
+// Related returns wrapup documents similar to the one which has given ID using more_like_this query.
+func (s *elasticStore) Related(ctx context.Context, id string, size int) ([]*pb.RelatedWrapup, error) {
+	// the corpus is small, so accept terms which appear only once
+	mlt := elastic.NewMoreLikeThisQuery().
+		Field(relatedFields...).
+		LikeItems(elastic.NewMoreLikeThisQueryItem().Index(s.index).Type(typ).Id(id)).
+		MinTermFreq(1).
+		MinDocFreq(1).
+		Include(false)
+	query := elastic.NewBoolQuery().
+		Must(mlt).
+		MustNot(elastic.NewIdsQuery(typ).Ids(id), elastic.NewExistsQuery("delete_time"))
+
+	result, err := s.client.Search(s.index).Query(query).Version(true).Size(size).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search related documents in Elasticsearch")
+	}
+
+	related := make([]*pb.RelatedWrapup, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		wrapup, err := decodeWrapup(hit.Id, hit.Version, hit.Source)
+		if err != nil {
+			return nil, err
+		}
+		r := &pb.RelatedWrapup{
+			Wrapup: wrapup,
+		}
+		if hit.Score != nil {
+			r.Score = *hit.Score
+		}
+		related = append(related, r)
+	}
+	return related, nil
+}
+
+// SuggestTitles returns wrapup documents whose title starts with prefix.
+func (s *elasticStore) SuggestTitles(ctx context.Context, prefix string, size int) ([]*pb.TitleSuggestion, error) {
+	result, err := s.client.Search(s.index).SearchSource(esSuggestSource(prefix, size)).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to suggest titles in Elasticsearch")
+	}
+
+	suggestions := make([]*pb.TitleSuggestion, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		wrapup, err := decodeWrapup(hit.Id, nil, hit.Source)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &pb.TitleSuggestion{
+			Id:    wrapup.Id,
+			Title: wrapup.Title,
+		})
+	}
+	return suggestions, nil
+}
+
+// esSuggestSource returns the search source to find size wrapup documents not in the trash whose title starts with prefix.
+// Completion suggester cannot exclude the documents in the trash by query,
+// so titles are matched by the prefix of normalized_title and deduplicated by collapsing it, same as memoryStore.
+func esSuggestSource(prefix string, size int) *elastic.SearchSource {
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewPrefixQuery("normalized_title", normalizeTitle(prefix))).
+		MustNot(elastic.NewExistsQuery("delete_time"))
+	return elastic.NewSearchSource().
+		Query(query).
+		Collapse(elastic.NewCollapseBuilder("normalized_title")).
+		SortBy(elastic.NewFieldSort("title.keyword"), elastic.NewFieldSort("id")).
+		FetchSourceContext(elastic.NewFetchSourceContext(true).Include("title")).
+		Size(size)
+}
+
+// Aggregate returns the statistics of wrapup documents not in the trash matched to filter.
+func (s *elasticStore) Aggregate(ctx context.Context, filter *Filter, termsSize int) (*pb.AggregateWrapupsResponse, error) {
+	query := elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("delete_time"))
+	if filter != nil {
+		query = query.Filter(esFilterQuery(filter.expr))
+	}
+
+	monthly := elastic.NewDateHistogramAggregation().
+		Script(elastic.NewScript(createTimeMillisScript)).
+		Interval("month").
+		MinDocCount(1).
+		SubAggregation("significant_terms", elastic.NewSignificantTextAggregation().Field("wrapup").Size(termsSize).FilterDuplicateText(true))
+	tags := elastic.NewTermsAggregation().Field("tags").Size(maxTags)
+	lengths := elastic.NewRangeAggregation().Script(elastic.NewScript(wrapupLengthScript))
+	for i, from := range wrapupLengthRanges {
+		if i == len(wrapupLengthRanges)-1 {
+			lengths = lengths.AddUnboundedTo(from)
+		} else {
+			lengths = lengths.AddRange(from, wrapupLengthRanges[i+1])
+		}
+	}
+
+	result, err := s.client.Search(s.index).Query(query).Size(0).
+		Aggregation("monthly", monthly).
+		Aggregation("tags", tags).
+		Aggregation("wrapup_lengths", lengths).
+		Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to aggregate documents")
+	}
+
+	res := &pb.AggregateWrapupsResponse{
+		TotalSize: result.TotalHits(),
+	}
+	if agg, ok := result.Aggregations.DateHistogram("monthly"); ok {
+		for _, bucket := range agg.Buckets {
+			startTime, err := ptypes.TimestampProto(time.Unix(0, int64(bucket.Key)*int64(time.Millisecond)))
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid date_histogram key")
+			}
+			period := &pb.PeriodBucket{
+				StartTime: startTime,
+				Count:     bucket.DocCount,
+			}
+			if terms, ok := bucket.Aggregations.SignificantTerms("significant_terms"); ok {
+				for _, term := range terms.Buckets {
+					period.SignificantTerms = append(period.SignificantTerms, &pb.TermBucket{
+						Key:   term.Key,
+						Count: term.DocCount,
+					})
+				}
+			}
+			res.Monthly = append(res.Monthly, period)
+		}
+	}
+	if agg, ok := result.Aggregations.Terms("tags"); ok {
+		for _, bucket := range agg.Buckets {
+			res.Tags = append(res.Tags, &pb.TermBucket{
+				Key:   fmt.Sprint(bucket.Key),
+				Count: bucket.DocCount,
+			})
+		}
+	}
+	if agg, ok := result.Aggregations.Range("wrapup_lengths"); ok {
+		for _, bucket := range agg.Buckets {
+			lengthBucket := &pb.RangeBucket{
+				Count: bucket.DocCount,
+			}
+			if bucket.From != nil {
+				lengthBucket.From = int64(*bucket.From)
+			}
+			if bucket.To != nil {
+				lengthBucket.To = int64(*bucket.To)
+			}
+			res.WrapupLengths = append(res.WrapupLengths, lengthBucket)
+		}
+	}
+	return res, nil
+}
+
+// Tags returns all tags attached to wrapup documents not in the trash with the number of documents.
+func (s *elasticStore) Tags(ctx context.Context) ([]*pb.Tag, error) {
+	query := elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("delete_time"))
+	agg := elastic.NewTermsAggregation().Field("tags").Size(maxTags)
+	result, err := s.client.Search(s.index).Query(query).Size(0).Aggregation("tags", agg).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to aggregate tags")
+	}
+
+	terms, ok := result.Aggregations.Terms("tags")
+	if !ok {
+		return nil, nil
+	}
+	tags := make([]*pb.Tag, 0, len(terms.Buckets))
+	for _, bucket := range terms.Buckets {
+		tags = append(tags, &pb.Tag{
+			Name:  fmt.Sprint(bucket.Key),
+			Count: bucket.DocCount,
+		})
+	}
+	return tags, nil
+}
+
+// RenameTag renames tag from to to in all wrapup documents and returns the number of updated documents.
+func (s *elasticStore) RenameTag(ctx context.Context, from, to, user string) (int64, error) {
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	now := ptypes.TimestampNow()
+	script := elastic.NewScript(renameTagScript).Param("from", from).Param("to", to).
+		Param("user", user).Param("seconds", now.Seconds).Param("nanos", now.Nanos)
+	res, err := s.client.UpdateByQuery(s.index).Query(elastic.NewTermQuery("tags", from)).Script(script).
+		Refresh("true").Do(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to rename tag")
+	}
+	return res.Updated, nil
+}
+
+// BackfillOwner sets user to created_by and updated_by of all wrapup documents which do not have them.
+func (s *elasticStore) BackfillOwner(ctx context.Context, user string) (int64, error) {
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	query := elastic.NewBoolQuery().Should(
+		elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("created_by")),
+		elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("updated_by")),
+	)
+	script := elastic.NewScript(backfillOwnerScript).Param("user", user)
+	res, err := s.client.UpdateByQuery(s.index).Query(query).Script(script).Refresh("true").Do(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to backfill owner")
+	}
+	return res.Updated, nil
+}
@@ -0,0 +1,461 @@
+package wuserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+
+	// compactThreshold is the number of log records after which the write-ahead log is compacted into the snapshot.
+	compactThreshold = 1000
+)
+
+// operations recorded in the write-ahead log
+const (
+	walPutWrapup    = "put_wrapup"
+	walDeleteWrapup = "delete_wrapup"
+	walPutRevision  = "put_revision"
+)
+
+// walRecord is a change recorded in the write-ahead log.
+// Records hold the resulting state instead of the operation, so applying the same record twice is harmless.
+type walRecord struct {
+	Op       string             `json:"op"`
+	Wrapup   *pb.Wrapup         `json:"wrapup,omitempty"`
+	ID       string             `json:"id,omitempty"`
+	Revision *pb.WrapupRevision `json:"revision,omitempty"`
+}
+
+// snapshot is all documents at the time when the write-ahead log is compacted.
+type snapshot struct {
+	Wrapups   []*pb.Wrapup         `json:"wrapups"`
+	Revisions []*pb.WrapupRevision `json:"revisions"`
+}
+
+// fileStore is the Store which persists documents to local data directory.
+// Documents are kept in memory and searched with the inverted index like memoryStore.
+//
+// Every change is appended to the write-ahead log and synced to disk before applied in memory,
+// so the change is not lost once it is returned successfully even if the process crashes.
+// When the log grows, it is compacted into the snapshot.
+// The data directory must not be shared by multiple processes.
+type fileStore struct {
+	// mem holds the documents applied from the snapshot and the write-ahead log.
+	mem    *memoryStore
+	logger *zap.Logger
+	dir    string
+
+	// writeMu serializes changes so that the order of log records is the same as the order of changes.
+	writeMu sync.Mutex
+	wal     *os.File
+	// size is the size of valid records in wal.
+	size int64
+	// records is the number of records in wal.
+	records int
+}
+
+// newFileStore loads the documents in dir and returns fileStore.
+// dir is created if it does not exist.
+func newFileStore(logger *zap.Logger, dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create data directory")
+	}
+	s := &fileStore{
+		mem:    newMemoryStore(),
+		logger: logger,
+		dir:    dir,
+	}
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	if s.records > 0 {
+		if err := s.compact(); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info("data directory loaded", zap.String("dir", dir), zap.Int("wrapups", len(s.mem.wrapups)))
+	return s, nil
+}
+
+// loadSnapshot loads the documents in the snapshot if it exists.
+func (s *fileStore) loadSnapshot() error {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, snapshotFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read snapshot")
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return errors.Wrap(err, "failed to decode snapshot")
+	}
+	for _, wrapup := range snap.Wrapups {
+		s.mem.putWrapup(wrapup)
+	}
+	for _, revision := range snap.Revisions {
+		s.mem.putRevision(revision)
+	}
+	return nil
+}
+
+// replay applies the records in the write-ahead log and opens it for appending.
+// The last record partially written or broken by crash is discarded.
+func (s *fileStore) replay() error {
+	wal, err := os.OpenFile(filepath.Join(s.dir, walFileName), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open write-ahead log")
+	}
+	// sync directory so that the log created now is not lost with the records appended to it
+	if err := syncDir(s.dir); err != nil {
+		wal.Close()
+		return err
+	}
+	r := bufio.NewReader(wal)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				s.logger.Warn("discard partially written record in write-ahead log", zap.Int64("offset", s.size))
+			}
+			break
+		}
+		if err != nil {
+			wal.Close()
+			return errors.Wrap(err, "failed to read write-ahead log")
+		}
+		var record walRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			// the last record may be broken if the crash happened before the sync completed,
+			// but broken record followed by others means the log is corrupted
+			if _, perr := r.Peek(1); perr == io.EOF {
+				s.logger.Warn("discard broken record in write-ahead log", zap.Int64("offset", s.size), zap.Error(err))
+				break
+			}
+			wal.Close()
+			return errors.Wrapf(err, "write-ahead log is corrupted at offset %d", s.size)
+		}
+		s.applyRecord(&record)
+		s.size += int64(len(line))
+		s.records++
+	}
+
+	if err := wal.Truncate(s.size); err != nil {
+		wal.Close()
+		return errors.Wrap(err, "failed to truncate write-ahead log")
+	}
+	if _, err := wal.Seek(s.size, io.SeekStart); err != nil {
+		wal.Close()
+		return errors.Wrap(err, "failed to seek write-ahead log")
+	}
+	s.wal = wal
+	return nil
+}
+
+// applyRecord applies record in memory. s.mem.mu must be held for writing.
+func (s *fileStore) applyRecord(record *walRecord) {
+	switch record.Op {
+	case walPutWrapup:
+		s.mem.putWrapup(record.Wrapup)
+	case walDeleteWrapup:
+		s.mem.deleteWrapup(record.ID)
+	case walPutRevision:
+		s.mem.putRevision(record.Revision)
+	}
+}
+
+// apply appends records to the write-ahead log and then applies them in memory.
+// s.writeMu must be held.
+func (s *fileStore) apply(records ...*walRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, record := range records {
+		b, err := json.Marshal(record)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode log record")
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	if _, err := s.wal.Write(buf.Bytes()); err != nil {
+		s.rollbackLog()
+		return errors.Wrap(err, "failed to write log record")
+	}
+	if err := s.wal.Sync(); err != nil {
+		s.rollbackLog()
+		return errors.Wrap(err, "failed to sync write-ahead log")
+	}
+	s.size += int64(buf.Len())
+	s.records += len(records)
+
+	s.mem.mu.Lock()
+	for _, record := range records {
+		s.applyRecord(record)
+	}
+	s.mem.mu.Unlock()
+
+	if s.records >= compactThreshold {
+		// the change is already persisted, so failure of compaction is not an error of the change
+		if err := s.compact(); err != nil {
+			s.logger.Error("failed to compact write-ahead log", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// rollbackLog removes the records partially written to the write-ahead log,
+// so that following records are not appended after broken one.
+func (s *fileStore) rollbackLog() {
+	if err := s.wal.Truncate(s.size); err != nil {
+		s.logger.Error("failed to truncate write-ahead log", zap.Error(err))
+	}
+	if _, err := s.wal.Seek(s.size, io.SeekStart); err != nil {
+		s.logger.Error("failed to seek write-ahead log", zap.Error(err))
+	}
+}
+
+// compact writes all documents to the snapshot and empties the write-ahead log.
+// The snapshot is replaced atomically, so either old or new one exists even if the process crashes.
+// s.writeMu must be held or s must not be used by others yet.
+func (s *fileStore) compact() error {
+	s.mem.mu.RLock()
+	snap := snapshot{
+		Wrapups: make([]*pb.Wrapup, 0, len(s.mem.wrapups)),
+	}
+	for id, wrapup := range s.mem.wrapups {
+		snap.Wrapups = append(snap.Wrapups, wrapup)
+		snap.Revisions = append(snap.Revisions, s.mem.revisions[id]...)
+	}
+	data, err := json.Marshal(&snap)
+	s.mem.mu.RUnlock()
+	if err != nil {
+		return errors.Wrap(err, "failed to encode snapshot")
+	}
+
+	if err := writeFileAtomic(filepath.Join(s.dir, snapshotFileName), data); err != nil {
+		return err
+	}
+	// records remaining in the log by crash here are applied again on start, which is harmless
+	if err := s.wal.Truncate(0); err != nil {
+		return errors.Wrap(err, "failed to truncate write-ahead log")
+	}
+	if _, err := s.wal.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "failed to seek write-ahead log")
+	}
+	if err := s.wal.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync write-ahead log")
+	}
+	s.size = 0
+	s.records = 0
+	return nil
+}
+
+// writeFileAtomic writes data to the temporary file and renames it to path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to write temporary file")
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to sync temporary file")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temporary file")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrap(err, "failed to rename temporary file")
+	}
+
+	// sync directory so that the rename is persisted
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir syncs the directory so that the entries created or renamed in it are persisted.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open directory")
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync directory")
+	}
+	return nil
+}
+
+// Create stores new wrapup document and returns it with the assigned ID.
+func (s *fileStore) Create(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	doc := cloneWrapup(wrapup)
+	doc.Id = id
+	doc.Version = 1
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.apply(&walRecord{Op: walPutWrapup, Wrapup: doc}); err != nil {
+		return nil, err
+	}
+	return cloneWrapup(doc), nil
+}
+
+// Get returns the wrapup document which has given ID, including the one in the trash.
+func (s *fileStore) Get(ctx context.Context, id string) (*pb.Wrapup, error) {
+	return s.mem.Get(ctx, id)
+}
+
+// Update replaces the wrapup document which has the same ID with given one.
+func (s *fileStore) Update(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mem.mu.RLock()
+	doc, err := s.mem.updatedWrapup(wrapup)
+	s.mem.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(&walRecord{Op: walPutWrapup, Wrapup: doc}); err != nil {
+		return nil, err
+	}
+	return cloneWrapup(doc), nil
+}
+
+// List returns the wrapup documents matched to query.
+func (s *fileStore) List(ctx context.Context, q *ListQuery) (*pb.ListWrapupsResponse, error) {
+	return s.mem.List(ctx, q)
+}
+
+// Purge removes the wrapup documents deleted before deadline and their revisions permanently.
+func (s *fileStore) Purge(ctx context.Context, deadline time.Time) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mem.mu.RLock()
+	ids := s.mem.expired(deadline)
+	s.mem.mu.RUnlock()
+
+	records := make([]*walRecord, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, &walRecord{Op: walDeleteWrapup, ID: id})
+	}
+	if err := s.apply(records...); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// Search runs full-text search across all text fields of wrapup documents not in the trash.
+func (s *fileStore) Search(ctx context.Context, q *SearchQuery) (*pb.SearchWrapupsResponse, error) {
+	return s.mem.Search(ctx, q)
+}
+
+// Related returns wrapup documents similar to the one which has given ID.
+func (s *fileStore) Related(ctx context.Context, id string, size int) ([]*pb.RelatedWrapup, error) {
+	return s.mem.Related(ctx, id, size)
+}
+
+// SuggestTitles returns wrapup documents whose normalized title starts with normalized prefix.
+func (s *fileStore) SuggestTitles(ctx context.Context, prefix string, size int) ([]*pb.TitleSuggestion, error) {
+	return s.mem.SuggestTitles(ctx, prefix, size)
+}
+
+// FindByTitle returns the wrapup document not in the trash whose normalized title is the same as given one.
+func (s *fileStore) FindByTitle(ctx context.Context, title string) (*pb.Wrapup, error) {
+	return s.mem.FindByTitle(ctx, title)
+}
+
+// Aggregate returns the statistics of wrapup documents not in the trash matched to filter.
+func (s *fileStore) Aggregate(ctx context.Context, filter *Filter, termsSize int) (*pb.AggregateWrapupsResponse, error) {
+	return s.mem.Aggregate(ctx, filter, termsSize)
+}
+
+// Tags returns all tags attached to wrapup documents not in the trash with the number of documents.
+func (s *fileStore) Tags(ctx context.Context) ([]*pb.Tag, error) {
+	return s.mem.Tags(ctx)
+}
+
+// RenameTag renames tag from to to in all wrapup documents and returns the number of updated documents.
+func (s *fileStore) RenameTag(ctx context.Context, from, to, user string) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mem.mu.RLock()
+	renamed := s.mem.renamedWrapups(from, to, user)
+	s.mem.mu.RUnlock()
+
+	records := make([]*walRecord, 0, len(renamed))
+	for _, doc := range renamed {
+		records = append(records, &walRecord{Op: walPutWrapup, Wrapup: doc})
+	}
+	if err := s.apply(records...); err != nil {
+		return 0, err
+	}
+	return int64(len(renamed)), nil
+}
+
+// BackfillOwner sets user to created_by and updated_by of all wrapup documents which do not have them.
+func (s *fileStore) BackfillOwner(ctx context.Context, user string) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mem.mu.RLock()
+	backfilled := s.mem.ownerlessWrapups(user)
+	s.mem.mu.RUnlock()
+
+	records := make([]*walRecord, 0, len(backfilled))
+	for _, doc := range backfilled {
+		records = append(records, &walRecord{Op: walPutWrapup, Wrapup: doc})
+	}
+	if err := s.apply(records...); err != nil {
+		return 0, err
+	}
+	return int64(len(backfilled)), nil
+}
+
+// SaveRevision stores the given contents of wrapup document as its revision numbered by its version.
+func (s *fileStore) SaveRevision(ctx context.Context, wrapup *pb.Wrapup) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mem.mu.RLock()
+	revision, err := s.mem.newRevision(wrapup)
+	s.mem.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return s.apply(&walRecord{Op: walPutRevision, Revision: revision})
+}
+
+// ListRevisions returns all revisions of the wrapup document ordered by revision number.
+func (s *fileStore) ListRevisions(ctx context.Context, id string) ([]*pb.WrapupRevision, error) {
+	return s.mem.ListRevisions(ctx, id)
+}
+
+// GetRevision returns the revision of the wrapup document.
+func (s *fileStore) GetRevision(ctx context.Context, id string, revision int32) (*pb.WrapupRevision, error) {
+	return s.mem.GetRevision(ctx, id, revision)
+}
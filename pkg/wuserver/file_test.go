@@ -0,0 +1,253 @@
+package wuserver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+)
+
+func tempDataDir(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "wrapups-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func openFileStore(t *testing.T, dir string) *fileStore {
+	t.Helper()
+	s, err := newFileStore(zap.NewNop(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// appendLog appends data to the write-ahead log in dir as if it is written before crash.
+func appendLog(t *testing.T, dir, data string) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// populate makes changes of all kinds of log records and returns the remaining document.
+func populate(t *testing.T, s *fileStore) *pb.Wrapup {
+	t.Helper()
+	ctx := context.Background()
+	doc, err := s.Create(ctx, &pb.Wrapup{Title: "Attention Is All You Need", Tags: []string{"nlp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveRevision(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	doc.Wrapup = "transformer"
+	if doc, err = s.Update(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RenameTag(ctx, "nlp", "language", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := s.Create(ctx, &pb.Wrapup{Title: "Deleted", DeleteTime: &timestamp.Timestamp{Seconds: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveRevision(ctx, deleted); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Purge(ctx, time.Now()); err != nil || n != 1 {
+		t.Fatalf("Purge() = %d, %v, want 1", n, err)
+	}
+
+	doc, err = s.Get(ctx, doc.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// checkContents checks that s has only want and its revision, and that the index is rebuilt.
+func checkContents(t *testing.T, s *fileStore, want *pb.Wrapup) {
+	t.Helper()
+	ctx := context.Background()
+	got, err := s.Get(ctx, want.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("Get() = %v, want %v", got, want)
+	}
+	if len(s.mem.wrapups) != 1 || len(s.mem.revisions) != 1 {
+		t.Errorf("%d wrapups and revisions of %d documents are loaded, want 1 and 1", len(s.mem.wrapups), len(s.mem.revisions))
+	}
+	revisions, err := s.ListRevisions(ctx, want.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 1 {
+		t.Errorf("revisions = %v, want only revision 1", revisions)
+	}
+	res, err := s.Search(ctx, &SearchQuery{Query: "transformer", PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 1 || res.Results[0].Wrapup.Id != want.Id {
+		t.Errorf("search results = %v, want %s", res.Results, want.Id)
+	}
+}
+
+func TestFileStoreReplay(t *testing.T) {
+	dir, cleanup := tempDataDir(t)
+	defer cleanup()
+
+	s := openFileStore(t, dir)
+	doc := populate(t, s)
+	if s.records == 0 {
+		t.Fatal("changes are not recorded in write-ahead log")
+	}
+
+	// the snapshot is not written yet, so all documents are loaded from the log
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); !os.IsNotExist(err) {
+		t.Fatalf("snapshot exists before compaction: %v", err)
+	}
+	reopened := openFileStore(t, dir)
+	checkContents(t, reopened, doc)
+
+	// the log replayed on start is compacted into the snapshot
+	if reopened.records != 0 || reopened.size != 0 {
+		t.Errorf("records = %d, size = %d after start, want 0", reopened.records, reopened.size)
+	}
+	checkContents(t, openFileStore(t, dir), doc)
+}
+
+func TestFileStoreCompact(t *testing.T) {
+	dir, cleanup := tempDataDir(t)
+	defer cleanup()
+
+	s := openFileStore(t, dir)
+	doc := populate(t, s)
+	s.writeMu.Lock()
+	err := s.compact()
+	s.writeMu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 || s.records != 0 || s.size != 0 {
+		t.Errorf("log size = %d, records = %d, size = %d after compaction, want 0", info.Size(), s.records, s.size)
+	}
+
+	// changes after compaction are appended to the emptied log and applied over the snapshot
+	doc.Note = "after compaction"
+	if doc, err = s.Update(context.Background(), doc); err != nil {
+		t.Fatal(err)
+	}
+	if s.records != 1 {
+		t.Errorf("records = %d, want 1", s.records)
+	}
+	checkContents(t, openFileStore(t, dir), doc)
+}
+
+func TestFileStoreCompactThreshold(t *testing.T) {
+	dir, cleanup := tempDataDir(t)
+	defer cleanup()
+
+	s := openFileStore(t, dir)
+	ctx := context.Background()
+	doc, err := s.Create(ctx, &pb.Wrapup{Title: "Counter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < compactThreshold; i++ {
+		doc.Note = strconv.Itoa(i)
+		if doc, err = s.Update(ctx, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.records != 0 {
+		t.Errorf("records = %d after %d changes, want 0", s.records, compactThreshold)
+	}
+	got, err := openFileStore(t, dir).Get(ctx, doc.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, doc) {
+		t.Errorf("Get() = %v, want %v", got, doc)
+	}
+}
+
+func TestFileStoreBrokenLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"partially written record", `{"op":"put_wrapup","wrap`, false},
+		{"broken last record", "{\"op\":\"put_wrapup\",\x00\x00}\n", false},
+		{"broken record followed by others", "{\"op\":\"put_wrapup\",\x00\x00}\n{\"op\":\"delete_wrapup\",\"id\":\"x\"}\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, cleanup := tempDataDir(t)
+			defer cleanup()
+
+			s := openFileStore(t, dir)
+			doc, err := s.Create(context.Background(), &pb.Wrapup{Title: "Before crash"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			appendLog(t, dir, tt.data)
+
+			reopened, err := newFileStore(zap.NewNop(), dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("corrupted log is accepted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := reopened.Get(context.Background(), doc.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, doc) {
+				t.Errorf("Get() = %v, want %v", got, doc)
+			}
+
+			// the broken record is removed, so records appended later are read
+			doc.Note = "after crash"
+			if doc, err = reopened.Update(context.Background(), doc); err != nil {
+				t.Fatal(err)
+			}
+			got, err = openFileStore(t, dir).Get(context.Background(), doc.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Note != doc.Note {
+				t.Errorf("note = %q, want %q", got.Note, doc.Note)
+			}
+		})
+	}
+}
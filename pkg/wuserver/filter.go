@@ -0,0 +1,546 @@
+package wuserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+// Filter expression grammar:
+//
+//	expr       = and { "OR" and }
+//	and        = unary { [ "AND" ] unary }
+//	unary      = "NOT" unary | primary
+//	primary    = "(" expr ")" | comparison | value
+//	comparison = field operator value
+//	operator   = ":" | "=" | "!=" | "<" | "<=" | ">" | ">="
+//	value      = word | quoted string
+//
+// A value without field matches the wrapup field, which is the same behavior as older versions.
+// Quoted value on text field matches as a phrase.
+// Date value is either "2006-01-02" or RFC 3339 timestamp and is interpreted in UTC.
+//
+// Example: title:"attention" AND create_time>=2019-01-01 AND NOT note:draft
+
+type filterFieldKind int
+
+const (
+	textField filterFieldKind = iota
+	keywordField
+	numberField
+	dateField
+)
+
+// filterField is the field which can be used in filter expression.
+type filterField struct {
+	kind filterFieldKind
+	// key is the field name in the stored document.
+	key string
+}
+
+// filterFields is the list of fields which can be used in filter expression.
+var filterFields = map[string]filterField{
+	"title":       {textField, "title"},
+	"wrapup":      {textField, "wrapup"},
+	"comment":     {textField, "comment"},
+	"note":        {textField, "note"},
+	"author":      {textField, "metadata.authors"},
+	"venue":       {textField, "metadata.venue"},
+	"abstract":    {textField, "metadata.abstract"},
+	"doi":         {textField, "metadata.doi"},
+	"arxiv_id":    {textField, "metadata.arxiv_id"},
+	"url":         {textField, "metadata.url"},
+	"year":        {numberField, "metadata.year"},
+	"tag":         {keywordField, "tags"},
+	"tags":        {keywordField, "tags"},
+	"created_by":  {keywordField, "created_by"},
+	"updated_by":  {keywordField, "updated_by"},
+	"create_time": {dateField, "create_time"},
+	"update_time": {dateField, "update_time"},
+}
+
+// defaultFilterField is the field matched by the value without field name.
+const defaultFilterField = "wrapup"
+
+// filterExpr is a node of parsed filter expression.
+type filterExpr interface{}
+
+// andExpr matches if all of exprs match.
+type andExpr struct {
+	exprs []filterExpr
+}
+
+// orExpr matches if any of exprs matches.
+type orExpr struct {
+	exprs []filterExpr
+}
+
+// notExpr matches if expr does not match.
+type notExpr struct {
+	expr filterExpr
+}
+
+// compareExpr compares the field with the value.
+type compareExpr struct {
+	field filterField
+	// op is one of ":", "=", "!=", "<", "<=", ">", ">=".
+	op string
+	// text is the value for text and keyword fields.
+	text string
+	// phrase is true if the value is quoted.
+	phrase bool
+	// number is the value for number fields.
+	number int64
+	// from and to represent the range [from, to) of the value for date fields.
+	from, to time.Time
+}
+
+// Filter is the parsed filter expression passed to Store.
+// nil Filter matches all wrapup documents.
+type Filter struct {
+	expr filterExpr
+}
+
+// newFilter returns the Filter which matches if all of non-nil exprs match.
+// nil is returned if there is no expression.
+func newFilter(exprs ...filterExpr) *Filter {
+	and := &andExpr{}
+	for _, expr := range exprs {
+		if expr != nil {
+			and.exprs = append(and.exprs, expr)
+		}
+	}
+	switch len(and.exprs) {
+	case 0:
+		return nil
+	case 1:
+		return &Filter{expr: and.exprs[0]}
+	}
+	return &Filter{expr: and}
+}
+
+// FilterError is the error which indicates the filter expression is invalid.
+type FilterError struct {
+	// Pos is 1-based position of the bad token in filter expression.
+	Pos int
+	// Token is the bad token. Empty if the error occurs at the end of expression.
+	Token string
+	// Msg describes the error.
+	Msg string
+}
+
+func (e *FilterError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("invalid filter: %s at end of expression", e.Msg)
+	}
+	return fmt.Sprintf("invalid filter: %s at position %d near %q", e.Msg, e.Pos, e.Token)
+}
+
+type filterTokenKind int
+
+const (
+	tokenEOF filterTokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	// value is the token text. quotes of string are already removed.
+	value string
+	// raw is the token text as written in the expression.
+	raw string
+	pos int
+}
+
+// isWordRune reports whether r can be a part of word token.
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()"<>=!:`, r)
+}
+
+// tokenizeFilter splits filter expression into tokens.
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0, 16)
+	i := 0
+	for i < len(filter) {
+		r, size := utf8.DecodeRuneInString(filter[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+		start := i
+		switch {
+		case r == '(':
+			tokens = append(tokens, filterToken{tokenLParen, "(", "(", start + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{tokenRParen, ")", ")", start + 1})
+			i++
+		case r == '"':
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(filter) {
+				c := filter[i]
+				if c == '\\' && i+1 < len(filter) {
+					b.WriteByte(filter[i+1])
+					i += 2
+					continue
+				}
+				i++
+				if c == '"' {
+					closed = true
+					break
+				}
+				b.WriteByte(c)
+			}
+			if !closed {
+				return nil, &FilterError{start + 1, filter[start:], "unterminated string"}
+			}
+			tokens = append(tokens, filterToken{tokenString, b.String(), filter[start:i], start + 1})
+		case strings.ContainsRune("<>=!:", r):
+			op := string(r)
+			i++
+			if i < len(filter) && filter[i] == '=' && r != '=' && r != ':' {
+				op += "="
+				i++
+			}
+			if op == "!" {
+				return nil, &FilterError{start + 1, op, "unknown operator"}
+			}
+			tokens = append(tokens, filterToken{tokenOperator, op, op, start + 1})
+		default:
+			// ':' is allowed in the value after operator, e.g. create_time>=2019-01-01T00:00:00Z
+			afterOperator := len(tokens) > 0 && tokens[len(tokens)-1].kind == tokenOperator
+			for i < len(filter) {
+				r, size := utf8.DecodeRuneInString(filter[i:])
+				if !isWordRune(r) && !(afterOperator && r == ':') {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, filterToken{tokenWord, filter[start:i], filter[start:i], start + 1})
+		}
+	}
+	tokens = append(tokens, filterToken{kind: tokenEOF, pos: len(filter) + 1})
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// parseFilter parses filter expression.
+// nil is returned if filter is empty.
+func parseFilter(filter string) (filterExpr, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorAt(t, "unexpected token")
+	}
+	return expr, nil
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) errorAt(t filterToken, msg string) error {
+	return &FilterError{Pos: t.pos, Token: t.raw, Msg: msg}
+}
+
+func isKeyword(t filterToken, keyword string) bool {
+	return t.kind == tokenWord && t.value == keyword
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []filterExpr{expr}
+	for isKeyword(p.peek(), "OR") {
+		p.next()
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return &orExpr{exprs}, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []filterExpr{expr}
+	for {
+		t := p.peek()
+		if isKeyword(t, "AND") {
+			p.next()
+		} else if t.kind == tokenEOF || t.kind == tokenRParen || isKeyword(t, "OR") {
+			break
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return &andExpr{exprs}, nil
+}
+
+func (p *filterParser) parseUnary() (filterExpr, error) {
+	if isKeyword(p.peek(), "NOT") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterExpr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, "expected \")\"")
+		}
+		return expr, nil
+	case tokenString:
+		return &compareExpr{field: filterFields[defaultFilterField], op: ":", text: t.value, phrase: true}, nil
+	case tokenWord:
+		if isKeyword(t, "AND") || isKeyword(t, "OR") {
+			return nil, p.errorAt(t, "unexpected keyword")
+		}
+		if p.peek().kind != tokenOperator {
+			return &compareExpr{field: filterFields[defaultFilterField], op: ":", text: t.value}, nil
+		}
+		return p.parseComparison(t)
+	case tokenEOF:
+		return nil, p.errorAt(t, "expected value")
+	}
+	return nil, p.errorAt(t, "unexpected token")
+}
+
+func (p *filterParser) parseComparison(name filterToken) (filterExpr, error) {
+	field, ok := filterFields[name.value]
+	if !ok {
+		return nil, p.errorAt(name, "unknown field")
+	}
+	op := p.next()
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, p.errorAt(value, "expected value")
+	}
+
+	// ':' in the value is allowed only for timestamp, so that "title:a:b" is not taken as title "a:b"
+	if i := strings.IndexByte(value.raw, ':'); i >= 0 && value.kind == tokenWord && field.kind != dateField {
+		return nil, &FilterError{Pos: value.pos + i, Token: value.raw[i:], Msg: "unexpected \":\". quote the value to include it"}
+	}
+
+	expr := &compareExpr{field: field, op: op.value}
+	switch field.kind {
+	case textField, keywordField:
+		if op.value != ":" && op.value != "=" && op.value != "!=" {
+			return nil, p.errorAt(op, fmt.Sprintf("operator not supported for field %s", name.value))
+		}
+		expr.text = value.value
+		expr.phrase = value.kind == tokenString
+		if field.key == "tags" {
+			// tags are normalized in the same way as the ones in documents
+			expr.text = normalizeTag(value.value)
+			if expr.text == "" {
+				return nil, p.errorAt(value, "expected tag")
+			}
+		}
+	case numberField:
+		n, err := strconv.ParseInt(value.value, 10, 64)
+		if err != nil {
+			return nil, p.errorAt(value, "expected number")
+		}
+		expr.number = n
+	case dateField:
+		from, to, err := parseFilterDate(value.value)
+		if err != nil {
+			return nil, p.errorAt(value, "expected date like 2006-01-02 or 2006-01-02T15:04:05Z")
+		}
+		expr.from, expr.to = from, to
+	}
+	return expr, nil
+}
+
+// parseFilterDate parses date value and returns the range [from, to) which the value represents.
+// Date like 2006-01-02 represents the whole day, and timestamp represents the second.
+func parseFilterDate(value string) (time.Time, time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, t.AddDate(0, 0, 1), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	t = t.UTC().Truncate(time.Second)
+	return t, t.Add(time.Second), nil
+}
+
+// Match reports whether wrapup matches the filter.
+// This is used by the backends which evaluate filter by themselves,
+// and behaves the same as the query built by esFilterQuery.
+func (f *Filter) Match(wrapup *pb.Wrapup) bool {
+	if f == nil {
+		return true
+	}
+	return matchFilter(f.expr, wrapup)
+}
+
+func matchFilter(expr filterExpr, wrapup *pb.Wrapup) bool {
+	switch e := expr.(type) {
+	case *andExpr:
+		for _, expr := range e.exprs {
+			if !matchFilter(expr, wrapup) {
+				return false
+			}
+		}
+		return true
+	case *orExpr:
+		for _, expr := range e.exprs {
+			if matchFilter(expr, wrapup) {
+				return true
+			}
+		}
+		return false
+	case *notExpr:
+		return !matchFilter(e.expr, wrapup)
+	case *compareExpr:
+		matched := matchCompare(e, wrapup)
+		if e.op == "!=" {
+			return !matched
+		}
+		return matched
+	}
+	return true
+}
+
+// matchCompare evaluates comparison.
+// Operator "!=" is treated as "=" here and negated by the caller.
+// Like Elasticsearch, comparison on missing field never matches.
+func matchCompare(e *compareExpr, wrapup *pb.Wrapup) bool {
+	switch e.field.kind {
+	case textField:
+		query := analyzeTerms(e.text)
+		if len(query) == 0 {
+			return false
+		}
+		values := textValues(wrapup, e.field.key)
+		if e.phrase {
+			for _, value := range values {
+				if containsPhrase(analyzeTerms(value), query) {
+					return true
+				}
+			}
+			return false
+		}
+		terms := make(map[string]bool)
+		for _, value := range values {
+			for _, term := range analyzeTerms(value) {
+				terms[term] = true
+			}
+		}
+		for _, term := range query {
+			if !terms[term] {
+				return false
+			}
+		}
+		return true
+	case keywordField:
+		for _, value := range keywordValues(wrapup, e.field.key) {
+			if value == e.text {
+				return true
+			}
+		}
+		return false
+	case numberField:
+		// metadata.year is the only number field, and 0 is not stored
+		if wrapup.Metadata == nil || wrapup.Metadata.Year == 0 {
+			return false
+		}
+		year := int64(wrapup.Metadata.Year)
+		switch e.op {
+		case "<":
+			return year < e.number
+		case "<=":
+			return year <= e.number
+		case ">":
+			return year > e.number
+		case ">=":
+			return year >= e.number
+		}
+		return year == e.number
+	case dateField:
+		var ts *timestamp.Timestamp
+		if e.field.key == "create_time" {
+			ts = wrapup.CreateTime
+		} else {
+			ts = wrapup.UpdateTime
+		}
+		if ts == nil {
+			return false
+		}
+		switch e.op {
+		case "<":
+			return ts.Seconds < e.from.Unix()
+		case "<=":
+			return ts.Seconds < e.to.Unix()
+		case ">":
+			return ts.Seconds >= e.to.Unix()
+		case ">=":
+			return ts.Seconds >= e.from.Unix()
+		}
+		return ts.Seconds >= e.from.Unix() && ts.Seconds < e.to.Unix()
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package wuserver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+// textCompare returns the comparison of text or keyword field.
+func textCompare(name, op, value string, phrase bool) *compareExpr {
+	return &compareExpr{field: filterFields[name], op: op, text: value, phrase: phrase}
+}
+
+func TestParseFilter(t *testing.T) {
+	day := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	ts := time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		filter string
+		want   filterExpr
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"attention", textCompare("wrapup", ":", "attention", false)},
+		{`"attention is"`, textCompare("wrapup", ":", "attention is", true)},
+		{`title:"a:b"`, textCompare("title", ":", "a:b", true)},
+		{`note="with \"quote\""`, textCompare("note", "=", `with "quote"`, true)},
+		{"tags!=ML", textCompare("tags", "!=", "ML", false)},
+		{`tag:" ml "`, textCompare("tag", ":", "ml", true)},
+		{"created_by:alice", textCompare("created_by", ":", "alice", false)},
+		{"year>=2017", &compareExpr{field: filterFields["year"], op: ">=", number: 2017}},
+		{"create_time<2019-01-01", &compareExpr{field: filterFields["create_time"], op: "<", from: day("2019-01-01"), to: day("2019-01-02")}},
+		{"update_time=2019-01-01T18:00:00+09:00", &compareExpr{field: filterFields["update_time"], op: "=", from: ts, to: ts.Add(time.Second)}},
+		{"a b", &andExpr{[]filterExpr{textCompare("wrapup", ":", "a", false), textCompare("wrapup", ":", "b", false)}}},
+		{"a AND b OR c", &orExpr{[]filterExpr{
+			&andExpr{[]filterExpr{textCompare("wrapup", ":", "a", false), textCompare("wrapup", ":", "b", false)}},
+			textCompare("wrapup", ":", "c", false),
+		}}},
+		{"(a OR b) c", &andExpr{[]filterExpr{
+			&orExpr{[]filterExpr{textCompare("wrapup", ":", "a", false), textCompare("wrapup", ":", "b", false)}},
+			textCompare("wrapup", ":", "c", false),
+		}}},
+		{"NOT NOT note:draft", &notExpr{&notExpr{textCompare("note", ":", "draft", false)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			got, err := parseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFilter() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterError(t *testing.T) {
+	tests := []struct {
+		filter string
+		pos    int
+		token  string
+		msg    string
+	}{
+		{"title:a:b", 8, ":b", `unexpected ":". quote the value to include it`},
+		{"tag=ml:x", 7, ":x", `unexpected ":". quote the value to include it`},
+		{"foo:bar", 1, "foo", "unknown field"},
+		{"title:", 7, "", "expected value"},
+		{"title:(a)", 7, "(", "expected value"},
+		{"year:abc", 6, "abc", "expected number"},
+		{"create_time>=yesterday", 14, "yesterday", "expected date like 2006-01-02 or 2006-01-02T15:04:05Z"},
+		{"title>x", 6, ">", "operator not supported for field title"},
+		{`tag:" "`, 5, `" "`, "expected tag"},
+		{"(a OR b", 8, "", `expected ")"`},
+		{"a)", 2, ")", "unexpected token"},
+		{`note:"draft`, 6, `"draft`, "unterminated string"},
+		{"a ! b", 3, "!", "unknown operator"},
+		{"AND a", 1, "AND", "unexpected keyword"},
+		{"a OR", 5, "", "expected value"},
+		{"NOT", 4, "", "expected value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			_, err := parseFilter(tt.filter)
+			ferr, ok := err.(*FilterError)
+			if !ok {
+				t.Fatalf("err = %v, want FilterError", err)
+			}
+			if ferr.Pos != tt.pos || ferr.Token != tt.token || ferr.Msg != tt.msg {
+				t.Errorf("err = {%d %q %q}, want {%d %q %q}", ferr.Pos, ferr.Token, ferr.Msg, tt.pos, tt.token, tt.msg)
+			}
+		})
+	}
+}
+
+func TestFilterErrorMessage(t *testing.T) {
+	err := &FilterError{Pos: 8, Token: ":b", Msg: "unexpected \":\""}
+	if want := `invalid filter: unexpected ":" at position 8 near ":b"`; err.Error() != want {
+		t.Errorf("Error() = %s, want %s", err.Error(), want)
+	}
+	err = &FilterError{Pos: 7, Msg: "expected value"}
+	if want := "invalid filter: expected value at end of expression"; err.Error() != want {
+		t.Errorf("Error() = %s, want %s", err.Error(), want)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	wrapup := &pb.Wrapup{
+		Title:      "Attention Is All You Need",
+		Wrapup:     "transformer replaces recurrence with attention",
+		Note:       "draft",
+		Tags:       []string{"ml", "nlp"},
+		CreatedBy:  "alice",
+		CreateTime: &timestamp.Timestamp{Seconds: time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC).Unix()},
+		Metadata:   &pb.PaperMetadata{Year: 2017, Authors: []string{"Ashish Vaswani"}},
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"attention", true},
+		{"recurrence attention", true},
+		{`"attention with"`, false},
+		{`title:"all you need"`, true},
+		{"tag:ml", true},
+		{`tag:" ml "`, true},
+		{"tag:ML", false},
+		{"tags!=cv", true},
+		{"created_by:alice AND NOT created_by:bob", true},
+		{"author:vaswani", true},
+		{"year>2017 OR year<2017", false},
+		{"year<=2017", true},
+		{"create_time=2019-01-01", true},
+		{"create_time>2019-01-01", false},
+		{"create_time>=2019-01-01T12:00:00Z", true},
+		{"update_time<2020-01-01", false},
+		{"venue:neurips", false},
+		{"note:draft (tag:cv OR tag:nlp)", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := parseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := newFilter(expr).Match(wrapup); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package wuserver
+
+import (
+	"math"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+const (
+	// bm25K1 and bm25B are the parameters of BM25, which are the same as the default of Elasticsearch.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// indexedFields is the text fields indexed by invertedIndex.
+var indexedFields = []string{"title", "wrapup", "comment", "note", "metadata.authors", "metadata.abstract"}
+
+// invertedIndex is the full-text index of wrapup documents used by the backends other than Elasticsearch.
+// invertedIndex is not safe for concurrent use.
+type invertedIndex struct {
+	fields map[string]*fieldIndex
+}
+
+// fieldIndex is the inverted index of one text field.
+type fieldIndex struct {
+	// postings maps term to the IDs of the documents which contain the term.
+	postings map[string]map[string]bool
+	// docs maps document ID to the frequency of each term in the field of the document.
+	docs map[string]map[string]int
+	// lengths is the number of terms in the field of each document.
+	lengths map[string]int
+	// totalLength is the sum of lengths.
+	totalLength int
+}
+
+func newInvertedIndex() *invertedIndex {
+	index := &invertedIndex{
+		fields: make(map[string]*fieldIndex, len(indexedFields)),
+	}
+	for _, field := range indexedFields {
+		index.fields[field] = &fieldIndex{
+			postings: make(map[string]map[string]bool),
+			docs:     make(map[string]map[string]int),
+			lengths:  make(map[string]int),
+		}
+	}
+	return index
+}
+
+// add indexes wrapup. The old contents of the same document must be removed beforehand.
+func (x *invertedIndex) add(wrapup *pb.Wrapup) {
+	for key, f := range x.fields {
+		var terms []string
+		for _, value := range textValues(wrapup, key) {
+			terms = append(terms, analyzeTerms(value)...)
+		}
+		if len(terms) == 0 {
+			continue
+		}
+		freq := make(map[string]int)
+		for _, term := range terms {
+			freq[term]++
+			if f.postings[term] == nil {
+				f.postings[term] = make(map[string]bool)
+			}
+			f.postings[term][wrapup.Id] = true
+		}
+		f.docs[wrapup.Id] = freq
+		f.lengths[wrapup.Id] = len(terms)
+		f.totalLength += len(terms)
+	}
+}
+
+// remove removes the document which has given ID from the index.
+func (x *invertedIndex) remove(id string) {
+	for _, f := range x.fields {
+		for term := range f.docs[id] {
+			delete(f.postings[term], id)
+			if len(f.postings[term]) == 0 {
+				delete(f.postings, term)
+			}
+		}
+		f.totalLength -= f.lengths[id]
+		delete(f.docs, id)
+		delete(f.lengths, id)
+	}
+}
+
+// docFreq returns the number of documents which contain term.
+func (f *fieldIndex) docFreq(term string) int {
+	return len(f.postings[term])
+}
+
+// idf returns inverse document frequency of term in the same way as BM25 of Elasticsearch.
+func (f *fieldIndex) idf(term string) float64 {
+	n := float64(f.docFreq(term))
+	return math.Log(1 + (float64(len(f.docs))-n+0.5)/(n+0.5))
+}
+
+// score returns BM25 score of the document which has given ID for query terms,
+// and the query terms contained in the document.
+func (f *fieldIndex) score(id string, query []string) (float64, []string) {
+	freq := f.docs[id]
+	if len(freq) == 0 {
+		return 0, nil
+	}
+	avgLength := float64(f.totalLength) / float64(len(f.docs))
+	norm := bm25K1 * (1 - bm25B + bm25B*float64(f.lengths[id])/avgLength)
+	var score float64
+	var matched []string
+	for _, term := range query {
+		tf := float64(freq[term])
+		if tf == 0 {
+			continue
+		}
+		score += f.idf(term) * tf * (bm25K1 + 1) / (tf + norm)
+		matched = append(matched, term)
+	}
+	return score, matched
+}
+
+// candidates returns the IDs of the documents which contain any of query terms in any of fields.
+func (x *invertedIndex) candidates(fields []string, query []string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, field := range fields {
+		for _, term := range query {
+			for id := range x.fields[field].postings[term] {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,181 @@
+package wuserver
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+// postingIDs returns the sorted IDs of the documents which contain term in the field.
+func postingIDs(x *invertedIndex, field, term string) []string {
+	var ids []string
+	for id := range x.fields[field].postings[term] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestInvertedIndexAddRemove(t *testing.T) {
+	x := newInvertedIndex()
+	x.add(&pb.Wrapup{Id: "a", Title: "Graph Neural Networks", Wrapup: "graph graph"})
+	x.add(&pb.Wrapup{Id: "b", Title: "Neural Machine Translation"})
+
+	title := x.fields["title"]
+	if got := postingIDs(x, "title", "neural"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("postings of neural = %v, want [a b]", got)
+	}
+	if title.totalLength != 6 || title.lengths["a"] != 3 {
+		t.Errorf("totalLength = %d, lengths[a] = %d, want 6 and 3", title.totalLength, title.lengths["a"])
+	}
+	if got := x.fields["wrapup"].docs["a"]["graph"]; got != 2 {
+		t.Errorf("frequency of graph = %d, want 2", got)
+	}
+	if _, ok := x.fields["comment"].docs["a"]; ok {
+		t.Error("empty field is indexed")
+	}
+
+	x.remove("a")
+	if got := postingIDs(x, "title", "neural"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("postings of neural after remove = %v, want [b]", got)
+	}
+	if _, ok := title.postings["graph"]; ok {
+		t.Error("posting of removed term is left")
+	}
+	if _, ok := x.fields["wrapup"].postings["graph"]; ok {
+		t.Error("posting of removed term is left in other field")
+	}
+	if title.totalLength != 3 || len(title.docs) != 1 {
+		t.Errorf("totalLength = %d, docs = %d after remove, want 3 and 1", title.totalLength, len(title.docs))
+	}
+
+	// removing unknown document does nothing
+	x.remove("c")
+	if title.totalLength != 3 {
+		t.Errorf("totalLength = %d after removing unknown document, want 3", title.totalLength)
+	}
+}
+
+func TestFieldIndexScore(t *testing.T) {
+	x := newInvertedIndex()
+	x.add(&pb.Wrapup{Id: "a", Title: "apple banana"})
+	x.add(&pb.Wrapup{Id: "b", Title: "apple"})
+	f := x.fields["title"]
+
+	// idf = ln(1 + (2 - 1 + 0.5) / (1 + 0.5)) = ln 2
+	if got := f.idf("banana"); math.Abs(got-math.Ln2) > 1e-9 {
+		t.Errorf("idf(banana) = %v, want %v", got, math.Ln2)
+	}
+	// avgLength = 1.5, norm = 1.2 * (0.25 + 0.75 * 2 / 1.5) = 1.5, tf part = 2.2 / 2.5
+	score, matched := f.score("a", []string{"banana", "cherry"})
+	if want := math.Ln2 * 2.2 / 2.5; math.Abs(score-want) > 1e-9 {
+		t.Errorf("score = %v, want %v", score, want)
+	}
+	if !reflect.DeepEqual(matched, []string{"banana"}) {
+		t.Errorf("matched = %v, want [banana]", matched)
+	}
+	if score, matched := f.score("c", []string{"apple"}); score != 0 || matched != nil {
+		t.Errorf("score of unknown document = %v, %v", score, matched)
+	}
+}
+
+func TestMemoryStoreIndexFollowsUpdates(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+	doc, err := s.Create(ctx, &pb.Wrapup{Title: "Quantum Supremacy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	search := func(query string) int {
+		t.Helper()
+		res, err := s.Search(ctx, &SearchQuery{Query: query, PageSize: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(res.Results)
+	}
+	if search("quantum") != 1 {
+		t.Fatal("created document is not found")
+	}
+
+	doc.Title = "Classical Simulation"
+	if doc, err = s.Update(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if search("quantum") != 0 || search("classical") != 1 {
+		t.Error("index is not updated by Update")
+	}
+	if got := s.index.fields["title"].totalLength; got != 2 {
+		t.Errorf("totalLength = %d after Update, want 2", got)
+	}
+
+	// documents in the trash stay in the index until purged, but are not searched
+	doc.DeleteTime = &timestamp.Timestamp{Seconds: 1}
+	if doc, err = s.Update(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if search("classical") != 0 {
+		t.Error("deleted document is searched")
+	}
+	doc.DeleteTime = nil
+	if doc, err = s.Update(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if search("classical") != 1 {
+		t.Error("undeleted document is not searched")
+	}
+
+	doc.DeleteTime = &timestamp.Timestamp{Seconds: 1}
+	if _, err = s.Update(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Purge(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if f := s.index.fields["title"]; len(f.postings) != 0 || f.totalLength != 0 {
+		t.Errorf("purged document is left in index: %v, %d", f.postings, f.totalLength)
+	}
+}
+
+func TestSignificantTerms(t *testing.T) {
+	x := newInvertedIndex()
+	docs := map[string]string{
+		"1": "graph embedding common",
+		"2": "graph embedding common",
+		"3": "graph common",
+		"4": "vision common",
+		"5": "vision common",
+		"6": "vision embedding common",
+	}
+	for id, text := range docs {
+		x.add(&pb.Wrapup{Id: id, Wrapup: text})
+	}
+
+	// graph: fg = 3/3, bg = 3/6, embedding: fg = 2/3 which is below the minimum doc count
+	// common appears in all documents, so it is not significant
+	terms := significantTerms(x.fields["wrapup"], []string{"1", "2", "3"}, len(docs), 10)
+	var keys []string
+	for _, term := range terms {
+		keys = append(keys, term.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"graph"}) || terms[0].Count != 3 {
+		t.Errorf("significant terms = %v", terms)
+	}
+
+	// no term is significant in the whole documents
+	all := []string{"1", "2", "3", "4", "5", "6"}
+	if terms := significantTerms(x.fields["wrapup"], all, len(docs), 10); len(terms) != 0 {
+		t.Errorf("terms of all documents are significant: %v", terms)
+	}
+	// graph and embedding have the same score, so ties are ordered by term and limited by size
+	terms = significantTerms(x.fields["wrapup"], []string{"1", "2", "3", "6"}, len(docs), 1)
+	if len(terms) != 1 || terms[0].Key != "embedding" {
+		t.Errorf("significant terms = %v, want only embedding", terms)
+	}
+}
@@ -0,0 +1,70 @@
+package wuserver
+
+import "fmt"
+
+// schemaVersion is the version of the settings and mapping of wrapup index.
+// When they are changed, schemaVersion must be incremented and the migration must be added to migrations.
+const schemaVersion = 4
+
+// indexSettings is the settings of wrapup index.
+// The number of wrapups is small, so one shard is enough and keeps the relevance scores accurate.
+const indexSettings = `{"number_of_shards": 1}`
+
+// indexMapping is the explicit mapping of wrapup index.
+// Fields not listed here are mapped dynamically.
+// The schema version is stored in _meta of the mapping.
+//
+//   - id is the same as _id, which is stored to sort by it because sorting by _id needs fielddata.
+//   - title has keyword sub-field to sort by title,
+//     and completion sub-field which is no longer used because it cannot exclude the documents in the trash.
+//   - timestamps are the objects encoded from google.protobuf.Timestamp.
+//   - normalized_title is the title normalized by normalizeTitle to check duplicates and suggest titles.
+//   - tags must be keyword to aggregate and filter by exact tag name.
+//   - created_by and updated_by must be keyword to filter by exact user name.
+var indexMapping = versionedIndexMapping(schemaVersion)
+
+// versionedIndexMapping returns indexMapping with the schema version replaced with version.
+// The mapping with older version is used to add new fields before the documents are migrated.
+func versionedIndexMapping(version int) string {
+	return fmt.Sprintf(indexMappingTemplate, version)
+}
+
+// indexMappingTemplate is indexMapping with the verb of schema version.
+const indexMappingTemplate = `{
+	"_meta": {"schema_version": %d},
+	"properties": {
+		"id": {"type": "keyword"},
+		"title": {
+			"type": "text",
+			"fields": {
+				"keyword": {"type": "keyword", "ignore_above": 256},
+				"suggest": {"type": "completion", "max_input_length": 256}
+			}
+		},
+		"normalized_title": {"type": "keyword"},
+		"create_time": {
+			"properties": {
+				"seconds": {"type": "long"},
+				"nanos": {"type": "integer"}
+			}
+		},
+		"update_time": {
+			"properties": {
+				"seconds": {"type": "long"},
+				"nanos": {"type": "integer"}
+			}
+		},
+		"delete_time": {
+			"properties": {
+				"seconds": {"type": "long"},
+				"nanos": {"type": "integer"}
+			}
+		},
+		"tags": {"type": "keyword"},
+		"created_by": {"type": "keyword"},
+		"updated_by": {"type": "keyword"}
+	}
+}`
+
+// indexBody is the request body to create wrapup index.
+var indexBody = fmt.Sprintf(`{"settings": %s, "mappings": {"%s": %s}}`, indexSettings, typ, indexMapping)
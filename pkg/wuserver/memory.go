@@ -0,0 +1,542 @@
+package wuserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/pkg/errors"
+)
+
+// memoryStore is the Store which keeps all documents in memory.
+// All documents are lost when the process exits, so this is intended for development and demo.
+type memoryStore struct {
+	mu        sync.RWMutex
+	wrapups   map[string]*pb.Wrapup
+	revisions map[string][]*pb.WrapupRevision
+	index     *invertedIndex
+}
+
+// NewMemoryStore creates and returns new Store which keeps all documents in memory.
+func NewMemoryStore() Store {
+	return newMemoryStore()
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		wrapups:   make(map[string]*pb.Wrapup),
+		revisions: make(map[string][]*pb.WrapupRevision),
+		index:     newInvertedIndex(),
+	}
+}
+
+// putWrapup stores wrapup as is and updates the index. s.mu must be held for writing.
+func (s *memoryStore) putWrapup(wrapup *pb.Wrapup) {
+	if _, ok := s.wrapups[wrapup.Id]; ok {
+		s.index.remove(wrapup.Id)
+	}
+	s.wrapups[wrapup.Id] = wrapup
+	s.index.add(wrapup)
+}
+
+// deleteWrapup removes the wrapup document and its revisions. s.mu must be held for writing.
+func (s *memoryStore) deleteWrapup(id string) {
+	if _, ok := s.wrapups[id]; ok {
+		s.index.remove(id)
+	}
+	delete(s.wrapups, id)
+	delete(s.revisions, id)
+}
+
+// putRevision stores revision as is. Storing the same revision again replaces the old one.
+// Revisions are kept ordered by revision number. s.mu must be held for writing.
+func (s *memoryStore) putRevision(revision *pb.WrapupRevision) {
+	revisions := s.revisions[revision.WrapupId]
+	i := searchRevision(revisions, revision.Revision)
+	if i < len(revisions) && revisions[i].Revision == revision.Revision {
+		revisions[i] = revision
+		return
+	}
+	revisions = append(revisions, nil)
+	copy(revisions[i+1:], revisions[i:])
+	revisions[i] = revision
+	s.revisions[revision.WrapupId] = revisions
+}
+
+// searchRevision returns the index of revisions where the given revision number is or should be inserted.
+func searchRevision(revisions []*pb.WrapupRevision, revision int32) int {
+	return sort.Search(len(revisions), func(i int) bool {
+		return revisions[i].Revision >= revision
+	})
+}
+
+// newID returns random document ID in the same format as the ID generated by Elasticsearch.
+func newID() (string, error) {
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate ID")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// cloneWrapup returns deep copy of wrapup so that stored documents are not modified by callers.
+func cloneWrapup(wrapup *pb.Wrapup) *pb.Wrapup {
+	return proto.Clone(wrapup).(*pb.Wrapup)
+}
+
+// Create stores new wrapup document and returns it with the assigned ID.
+func (s *memoryStore) Create(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	doc := cloneWrapup(wrapup)
+	doc.Id = id
+	doc.Version = 1
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putWrapup(doc)
+	return cloneWrapup(doc), nil
+}
+
+// Get returns the wrapup document which has given ID, including the one in the trash.
+func (s *memoryStore) Get(ctx context.Context, id string) (*pb.Wrapup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	doc, ok := s.wrapups[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneWrapup(doc), nil
+}
+
+// Update replaces the wrapup document which has the same ID with given one.
+func (s *memoryStore) Update(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc, err := s.updatedWrapup(wrapup)
+	if err != nil {
+		return nil, err
+	}
+	s.putWrapup(doc)
+	return cloneWrapup(doc), nil
+}
+
+// updatedWrapup returns the copy of wrapup with the next version to replace the stored one.
+// ErrConflict is returned if the stored document has been changed since wrapup was read.
+// s.mu must be held.
+func (s *memoryStore) updatedWrapup(wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	current, ok := s.wrapups[wrapup.Id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if current.Version != wrapup.Version {
+		return nil, ErrConflict
+	}
+	doc := cloneWrapup(wrapup)
+	doc.Version++
+	return doc, nil
+}
+
+// memoryHit is a document matched to query with its sort values.
+type memoryHit struct {
+	wrapup *pb.Wrapup
+	score  float64
+	// sort is the sort values in the same form as Elasticsearch, which are used as page token.
+	sort []interface{}
+}
+
+// List returns the wrapup documents matched to query.
+// Relevance of all documents is the same because filter does not score documents.
+func (s *memoryStore) List(ctx context.Context, q *ListQuery) (*pb.ListWrapupsResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var hits []*memoryHit
+	for _, doc := range s.wrapups {
+		if (doc.DeleteTime != nil) != q.Deleted || !q.Filter.Match(doc) {
+			continue
+		}
+		hits = append(hits, &memoryHit{wrapup: doc})
+	}
+	hits, next, total, err := pageHits(hits, q.OrderBy, q.PageSize, q.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	wrapups := make([]*pb.Wrapup, 0, len(hits))
+	for _, hit := range hits {
+		wrapups = append(wrapups, cloneWrapup(hit.wrapup))
+	}
+	return &pb.ListWrapupsResponse{
+		Count:         int32(total),
+		Wrapups:       wrapups,
+		NextPageToken: next,
+		TotalSize:     int32(total),
+	}, nil
+}
+
+// pageHits sorts hits by orders and returns the page specified by size and token,
+// the token of the next page and the number of all hits.
+func pageHits(hits []*memoryHit, orders []Order, size int, token string) ([]*memoryHit, string, int, error) {
+	descending := make([]bool, 0, len(orders)+2)
+	for _, order := range orders {
+		descending = append(descending, order.Descending)
+		if order.Field == "create_time" {
+			// create_time is sorted by seconds and nanos
+			descending = append(descending, order.Descending)
+		}
+	}
+	descending = append(descending, false)
+	for _, hit := range hits {
+		hit.sort = memorySortValues(hit, orders)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		return compareSortValues(hits[i].sort, hits[j].sort, descending) < 0
+	})
+
+	total := len(hits)
+	if token != "" {
+		searchAfter, err := decodePageToken(token)
+		if err != nil || !matchSortValues(searchAfter, memorySortValues(&memoryHit{wrapup: &pb.Wrapup{}}, orders)) {
+			return nil, "", 0, ErrInvalidPageToken
+		}
+		i := sort.Search(len(hits), func(i int) bool {
+			return compareSortValues(hits[i].sort, searchAfter, descending) > 0
+		})
+		hits = hits[i:]
+	}
+	var next string
+	if len(hits) > size {
+		hits = hits[:size]
+		if size > 0 {
+			var err error
+			if next, err = encodePageToken(hits[len(hits)-1].sort); err != nil {
+				return nil, "", 0, err
+			}
+		}
+	}
+	return hits, next, total, nil
+}
+
+// memorySortValues returns the sort values of hit for orders. ID is appended as tiebreaker.
+func memorySortValues(hit *memoryHit, orders []Order) []interface{} {
+	values := make([]interface{}, 0, len(orders)+2)
+	for _, order := range orders {
+		switch order.Field {
+		case "create_time":
+			var seconds, nanos int64
+			if t := hit.wrapup.CreateTime; t != nil {
+				seconds, nanos = t.Seconds, int64(t.Nanos)
+			}
+			values = append(values, seconds, nanos)
+		case "title":
+			values = append(values, hit.wrapup.Title)
+		case "relevance":
+			values = append(values, hit.score)
+		}
+	}
+	return append(values, hit.wrapup.Id)
+}
+
+// matchSortValues reports whether the sort values decoded from page token have the same number and types as want.
+func matchSortValues(values, want []interface{}) bool {
+	if len(values) != len(want) {
+		return false
+	}
+	for i := range values {
+		_, isString := values[i].(string)
+		_, wantString := want[i].(string)
+		if _, isNumber := values[i].(json.Number); isString == isNumber || isString != wantString {
+			return false
+		}
+	}
+	return true
+}
+
+// compareSortValues compares two sort values like strings.Compare.
+// The values may be the ones decoded from page token, so numbers are compared as float64.
+func compareSortValues(a, b []interface{}, descending []bool) int {
+	for i := range a {
+		c := compareSortValue(a[i], b[i])
+		if descending[i] {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareSortValue(a, b interface{}) int {
+	if as, ok := a.(string); ok {
+		bs, _ := b.(string)
+		return strings.Compare(as, bs)
+	}
+	af, bf := sortNumber(a), sortNumber(b)
+	switch {
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	}
+	return 0
+}
+
+func sortNumber(v interface{}) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	}
+	return 0
+}
+
+// Purge removes the wrapup documents deleted before deadline and their revisions permanently.
+func (s *memoryStore) Purge(ctx context.Context, deadline time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := s.expired(deadline)
+	for _, id := range ids {
+		s.deleteWrapup(id)
+	}
+	return int64(len(ids)), nil
+}
+
+// expired returns the IDs of the wrapup documents deleted before deadline. s.mu must be held.
+func (s *memoryStore) expired(deadline time.Time) []string {
+	var ids []string
+	for id, doc := range s.wrapups {
+		if doc.DeleteTime != nil && doc.DeleteTime.Seconds <= deadline.Unix() {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// FindByTitle returns the wrapup document not in the trash whose normalized title is the same as given one.
+func (s *memoryStore) FindByTitle(ctx context.Context, title string) (*pb.Wrapup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	normalized := normalizeTitle(title)
+	var found *pb.Wrapup
+	for _, doc := range s.wrapups {
+		if doc.DeleteTime != nil || normalizeTitle(doc.Title) != normalized {
+			continue
+		}
+		if found == nil || doc.Id < found.Id {
+			found = doc
+		}
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return cloneWrapup(found), nil
+}
+
+// SuggestTitles returns wrapup documents whose normalized title starts with normalized prefix.
+// Suggestions are ordered by title.
+func (s *memoryStore) SuggestTitles(ctx context.Context, prefix string, size int) ([]*pb.TitleSuggestion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prefix = normalizeTitle(prefix)
+	var suggestions []*pb.TitleSuggestion
+	seen := make(map[string]bool)
+	for _, doc := range s.wrapups {
+		title := normalizeTitle(doc.Title)
+		if doc.DeleteTime != nil || !strings.HasPrefix(title, prefix) || seen[title] {
+			continue
+		}
+		seen[title] = true
+		suggestions = append(suggestions, &pb.TitleSuggestion{
+			Id:    doc.Id,
+			Title: doc.Title,
+		})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Title != suggestions[j].Title {
+			return suggestions[i].Title < suggestions[j].Title
+		}
+		return suggestions[i].Id < suggestions[j].Id
+	})
+	if len(suggestions) > size {
+		suggestions = suggestions[:size]
+	}
+	return suggestions, nil
+}
+
+// Tags returns all tags attached to wrapup documents not in the trash with the number of documents.
+// Tags are ordered by the number of documents, and then by name.
+func (s *memoryStore) Tags(ctx context.Context) ([]*pb.Tag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return countTags(s.wrapups, nil), nil
+}
+
+// countTags counts tags of the wrapup documents not in the trash matched to filter like terms aggregation.
+func countTags(wrapups map[string]*pb.Wrapup, filter *Filter) []*pb.Tag {
+	counts := make(map[string]int64)
+	for _, doc := range wrapups {
+		if doc.DeleteTime != nil || !filter.Match(doc) {
+			continue
+		}
+		for _, tag := range doc.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]*pb.Tag, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, &pb.Tag{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	if len(tags) > maxTags {
+		tags = tags[:maxTags]
+	}
+	return tags
+}
+
+// RenameTag renames tag from to to in all wrapup documents and returns the number of updated documents.
+func (s *memoryStore) RenameTag(ctx context.Context, from, to, user string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	renamed := s.renamedWrapups(from, to, user)
+	for _, doc := range renamed {
+		s.putWrapup(doc)
+	}
+	return int64(len(renamed)), nil
+}
+
+// renamedWrapups returns the copies of the wrapup documents which have tag from with the tag renamed to to
+// and updated by user. s.mu must be held.
+func (s *memoryStore) renamedWrapups(from, to, user string) []*pb.Wrapup {
+	var renamed []*pb.Wrapup
+	now := ptypes.TimestampNow()
+	for _, doc := range s.wrapups {
+		doc = cloneWrapup(doc)
+		if renameTag(doc, from, to) {
+			doc.UpdateTime = now
+			doc.UpdatedBy = user
+			doc.Version++
+			renamed = append(renamed, doc)
+		}
+	}
+	return renamed
+}
+
+// renameTag replaces tag from with to in wrapup and removes duplicated tags.
+// false is returned if wrapup does not have tag from.
+func renameTag(wrapup *pb.Wrapup, from, to string) bool {
+	found := false
+	for i, tag := range wrapup.Tags {
+		if tag == from {
+			wrapup.Tags[i] = to
+			found = true
+		}
+	}
+	if found {
+		wrapup.Tags = normalizeTags(wrapup.Tags)
+	}
+	return found
+}
+
+// BackfillOwner sets user to created_by and updated_by of all wrapup documents which do not have them.
+func (s *memoryStore) BackfillOwner(ctx context.Context, user string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	backfilled := s.ownerlessWrapups(user)
+	for _, doc := range backfilled {
+		s.putWrapup(doc)
+	}
+	return int64(len(backfilled)), nil
+}
+
+// ownerlessWrapups returns the copies of the wrapup documents which do not have created_by or updated_by
+// with them set to user.
+// s.mu must be held.
+func (s *memoryStore) ownerlessWrapups(user string) []*pb.Wrapup {
+	var backfilled []*pb.Wrapup
+	for _, doc := range s.wrapups {
+		if doc.CreatedBy != "" && doc.UpdatedBy != "" {
+			continue
+		}
+		doc = cloneWrapup(doc)
+		if doc.CreatedBy == "" {
+			doc.CreatedBy = user
+		}
+		if doc.UpdatedBy == "" {
+			doc.UpdatedBy = user
+		}
+		doc.Version++
+		backfilled = append(backfilled, doc)
+	}
+	return backfilled
+}
+
+// SaveRevision stores the given contents of wrapup document as its revision numbered by its version.
+func (s *memoryStore) SaveRevision(ctx context.Context, wrapup *pb.Wrapup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revision, err := s.newRevision(wrapup)
+	if err != nil {
+		return err
+	}
+	s.putRevision(revision)
+	return nil
+}
+
+// newRevision returns new revision which has the given contents of wrapup document.
+// ErrConflict is returned if the revision already exists. s.mu must be held.
+func (s *memoryStore) newRevision(wrapup *pb.Wrapup) (*pb.WrapupRevision, error) {
+	number := int32(wrapup.Version)
+	revisions := s.revisions[wrapup.Id]
+	if i := searchRevision(revisions, number); i < len(revisions) && revisions[i].Revision == number {
+		return nil, ErrConflict
+	}
+	return &pb.WrapupRevision{
+		WrapupId:           wrapup.Id,
+		Revision:           number,
+		Wrapup:             cloneWrapup(wrapup),
+		RevisionCreateTime: ptypes.TimestampNow(),
+	}, nil
+}
+
+// ListRevisions returns all revisions of the wrapup document ordered by revision number.
+func (s *memoryStore) ListRevisions(ctx context.Context, id string) ([]*pb.WrapupRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	revisions := make([]*pb.WrapupRevision, 0, len(s.revisions[id]))
+	for _, revision := range s.revisions[id] {
+		revisions = append(revisions, proto.Clone(revision).(*pb.WrapupRevision))
+	}
+	return revisions, nil
+}
+
+// GetRevision returns the revision of the wrapup document.
+func (s *memoryStore) GetRevision(ctx context.Context, id string, revision int32) (*pb.WrapupRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	revisions := s.revisions[id]
+	i := searchRevision(revisions, revision)
+	if i == len(revisions) || revisions[i].Revision != revision {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(revisions[i]).(*pb.WrapupRevision), nil
+}
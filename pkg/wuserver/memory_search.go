@@ -0,0 +1,291 @@
+package wuserver
+
+import (
+	"context"
+	"sort"
+	"time"
+	"unicode/utf16"
+
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/pkg/errors"
+)
+
+const (
+	// relatedMaxQueryTerms is the maximum number of terms selected from the document by Related.
+	relatedMaxQueryTerms = 25
+	// relatedMinimumShouldMatch is the ratio of selected terms which related documents must contain.
+	relatedMinimumShouldMatch = 0.3
+
+	// significantTermsMinDocCount is the minimum number of documents which contain a significant term.
+	significantTermsMinDocCount = 3
+)
+
+// uniqueTerms returns analyzed terms of text without duplicates.
+func uniqueTerms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range analyzeTerms(text) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// Search runs full-text search across all text fields of wrapup documents not in the trash.
+// Like best_fields multi_match query of Elasticsearch,
+// the score of document is the best BM25 score of fields multiplied by the boost of the field.
+func (s *memoryStore) Search(ctx context.Context, q *SearchQuery) (*pb.SearchWrapupsResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := uniqueTerms(q.Query)
+	fields := make([]string, 0, len(searchFields))
+	for field := range searchFields {
+		fields = append(fields, field)
+	}
+
+	var hits []*memoryHit
+	matchedFields := make(map[string][]string)
+	for id := range s.index.candidates(fields, query) {
+		doc := s.wrapups[id]
+		if doc.DeleteTime != nil {
+			continue
+		}
+		var best float64
+		for field, boost := range searchFields {
+			score, matched := s.index.fields[field].score(id, query)
+			if len(matched) == 0 {
+				continue
+			}
+			matchedFields[id] = append(matchedFields[id], field)
+			if score*boost > best {
+				best = score * boost
+			}
+		}
+		if len(matchedFields[id]) > 0 {
+			hits = append(hits, &memoryHit{wrapup: doc, score: best})
+		}
+	}
+
+	hits, next, total, err := pageHits(hits, []Order{{Field: "relevance", Descending: true}}, q.PageSize, q.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	terms := make(map[string]bool, len(query))
+	for _, term := range query {
+		terms[term] = true
+	}
+	results := make([]*pb.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		fields := matchedFields[hit.wrapup.Id]
+		sortHighlightFields(fields)
+		highlights := make([]*pb.Highlight, 0, len(fields))
+		for _, field := range fields {
+			var fragments []string
+			for _, value := range textValues(hit.wrapup, field) {
+				fragments = append(fragments, highlightText(value, terms)...)
+			}
+			highlights = append(highlights, &pb.Highlight{
+				Field:     field,
+				Fragments: fragments,
+			})
+		}
+		results = append(results, &pb.SearchResult{
+			Wrapup:     cloneWrapup(hit.wrapup),
+			Score:      hit.score,
+			Highlights: highlights,
+		})
+	}
+	return &pb.SearchWrapupsResponse{
+		Results:       results,
+		NextPageToken: next,
+		TotalSize:     int32(total),
+	}, nil
+}
+
+// Related returns wrapup documents similar to the one which has given ID.
+// Like more_like_this query of Elasticsearch, the terms which characterize the document are selected by tf-idf,
+// and the documents which contain enough of them are scored by BM25.
+func (s *memoryStore) Related(ctx context.Context, id string, size int) ([]*pb.RelatedWrapup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	termScores := make(map[string]float64)
+	for _, field := range relatedFields {
+		f := s.index.fields[field]
+		for term, tf := range f.docs[id] {
+			if score := float64(tf) * f.idf(term); score > termScores[term] {
+				termScores[term] = score
+			}
+		}
+	}
+	query := make([]string, 0, len(termScores))
+	for term := range termScores {
+		query = append(query, term)
+	}
+	sort.Slice(query, func(i, j int) bool {
+		if termScores[query[i]] != termScores[query[j]] {
+			return termScores[query[i]] > termScores[query[j]]
+		}
+		return query[i] < query[j]
+	})
+	if len(query) > relatedMaxQueryTerms {
+		query = query[:relatedMaxQueryTerms]
+	}
+	minimumShouldMatch := int(float64(len(query)) * relatedMinimumShouldMatch)
+	if minimumShouldMatch < 1 {
+		minimumShouldMatch = 1
+	}
+
+	var hits []*memoryHit
+	for docID := range s.index.candidates(relatedFields, query) {
+		doc := s.wrapups[docID]
+		if docID == id || doc.DeleteTime != nil {
+			continue
+		}
+		var total float64
+		matchedTerms := make(map[string]bool)
+		for _, field := range relatedFields {
+			score, matched := s.index.fields[field].score(docID, query)
+			total += score
+			for _, term := range matched {
+				matchedTerms[term] = true
+			}
+		}
+		if len(matchedTerms) >= minimumShouldMatch {
+			hits = append(hits, &memoryHit{wrapup: doc, score: total})
+		}
+	}
+	hits, _, _, err := pageHits(hits, []Order{{Field: "relevance", Descending: true}}, size, "")
+	if err != nil {
+		return nil, err
+	}
+
+	related := make([]*pb.RelatedWrapup, 0, len(hits))
+	for _, hit := range hits {
+		related = append(related, &pb.RelatedWrapup{
+			Wrapup: cloneWrapup(hit.wrapup),
+			Score:  hit.score,
+		})
+	}
+	return related, nil
+}
+
+// Aggregate returns the statistics of wrapup documents not in the trash matched to filter.
+// Significant terms are scored by JLH like significant_text aggregation of Elasticsearch,
+// using all documents as background.
+func (s *memoryStore) Aggregate(ctx context.Context, filter *Filter, termsSize int) (*pb.AggregateWrapupsResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := &pb.AggregateWrapupsResponse{}
+	months := make(map[time.Time][]string)
+	lengths := make([]int64, len(wrapupLengthRanges))
+	for id, doc := range s.wrapups {
+		if doc.DeleteTime != nil || !filter.Match(doc) {
+			continue
+		}
+		res.TotalSize++
+		if doc.CreateTime != nil {
+			t := time.Unix(doc.CreateTime.Seconds, 0).UTC()
+			month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+			months[month] = append(months[month], id)
+		}
+		// length is counted in UTF-16 code units like Java
+		length := int64(len(utf16.Encode([]rune(doc.Wrapup))))
+		for i := len(wrapupLengthRanges) - 1; i >= 0; i-- {
+			if length >= wrapupLengthRanges[i] {
+				lengths[i]++
+				break
+			}
+		}
+	}
+
+	keys := make([]time.Time, 0, len(months))
+	for month := range months {
+		keys = append(keys, month)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Before(keys[j])
+	})
+	for _, month := range keys {
+		startTime, err := ptypes.TimestampProto(month)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid month")
+		}
+		ids := months[month]
+		res.Monthly = append(res.Monthly, &pb.PeriodBucket{
+			StartTime:        startTime,
+			Count:            int64(len(ids)),
+			SignificantTerms: significantTerms(s.index.fields["wrapup"], ids, len(s.wrapups), termsSize),
+		})
+	}
+
+	for _, tag := range countTags(s.wrapups, filter) {
+		res.Tags = append(res.Tags, &pb.TermBucket{
+			Key:   tag.Name,
+			Count: tag.Count,
+		})
+	}
+
+	for i, from := range wrapupLengthRanges {
+		bucket := &pb.RangeBucket{
+			From:  from,
+			Count: lengths[i],
+		}
+		if i < len(wrapupLengthRanges)-1 {
+			bucket.To = wrapupLengthRanges[i+1]
+		}
+		res.WrapupLengths = append(res.WrapupLengths, bucket)
+	}
+	return res, nil
+}
+
+// significantTerms returns at most size terms which appear in the documents of ids significantly more than in all documents.
+func significantTerms(f *fieldIndex, ids []string, total, size int) []*pb.TermBucket {
+	docFreq := make(map[string]int)
+	for _, id := range ids {
+		for term := range f.docs[id] {
+			docFreq[term]++
+		}
+	}
+
+	type scored struct {
+		term  string
+		count int
+		score float64
+	}
+	var terms []scored
+	for term, count := range docFreq {
+		if count < significantTermsMinDocCount {
+			continue
+		}
+		fg := float64(count) / float64(len(ids))
+		bg := float64(f.docFreq(term)) / float64(total)
+		if fg <= bg {
+			continue
+		}
+		terms = append(terms, scored{term: term, count: count, score: (fg - bg) * (fg / bg)})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].score != terms[j].score {
+			return terms[i].score > terms[j].score
+		}
+		return terms[i].term < terms[j].term
+	})
+	if len(terms) > size {
+		terms = terms[:size]
+	}
+
+	buckets := make([]*pb.TermBucket, 0, len(terms))
+	for _, t := range terms {
+		buckets = append(buckets, &pb.TermBucket{
+			Key:   t.term,
+			Count: int64(t.count),
+		})
+	}
+	return buckets
+}
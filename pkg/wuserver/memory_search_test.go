@@ -0,0 +1,155 @@
+package wuserver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+// putWrapups stores docs with their IDs as is, so that the order of tiebreaker is known.
+func putWrapups(s *memoryStore, docs ...*pb.Wrapup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range docs {
+		s.putWrapup(cloneWrapup(doc))
+	}
+}
+
+func TestMemorySearchRanking(t *testing.T) {
+	s := newMemoryStore()
+	putWrapups(s,
+		// title has the highest boost
+		&pb.Wrapup{Id: "title", Title: "transformer", Wrapup: "about language models"},
+		// the same term in shorter wrapup scores higher
+		&pb.Wrapup{Id: "short", Title: "short", Wrapup: "transformer model"},
+		&pb.Wrapup{Id: "long", Title: "long", Wrapup: "transformer model with many other words in this wrapup"},
+		&pb.Wrapup{Id: "note", Title: "note", Note: "transformer"},
+		&pb.Wrapup{Id: "unrelated", Title: "unrelated", Wrapup: "convolution"},
+		&pb.Wrapup{Id: "deleted", Title: "transformer", DeleteTime: &timestamp.Timestamp{Seconds: 1}},
+	)
+
+	res, err := s.Search(context.Background(), &SearchQuery{Query: "Transformer", PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for i, r := range res.Results {
+		ids = append(ids, r.Wrapup.Id)
+		if i > 0 && r.Score > res.Results[i-1].Score {
+			t.Errorf("results are not ordered by score: %v > %v", r.Score, res.Results[i-1].Score)
+		}
+	}
+	if want := []string{"title", "short", "long", "note"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("results = %v, want %v", ids, want)
+	}
+	if res.TotalSize != 4 || res.NextPageToken != "" {
+		t.Errorf("total_size = %d, next_page_token = %q, want 4 and empty", res.TotalSize, res.NextPageToken)
+	}
+
+	highlights := res.Results[0].Highlights
+	if len(highlights) != 1 || highlights[0].Field != "title" || highlights[0].Fragments[0] != "<em>transformer</em>" {
+		t.Errorf("highlights = %v", highlights)
+	}
+}
+
+func TestMemorySearchPaginationTies(t *testing.T) {
+	s := newMemoryStore()
+	ids := []string{"e", "b", "d", "a", "c"}
+	for _, id := range ids {
+		// the same contents make the same score, so ID breaks the ties
+		putWrapups(s, &pb.Wrapup{Id: id, Wrapup: "attention"})
+	}
+	putWrapups(s, &pb.Wrapup{Id: "z", Title: "attention"})
+
+	tests := []struct {
+		pageSize int
+		pages    [][]string
+	}{
+		{2, [][]string{{"z", "a"}, {"b", "c"}, {"d", "e"}}},
+		{4, [][]string{{"z", "a", "b", "c"}, {"d", "e"}}},
+		{6, [][]string{{"z", "a", "b", "c", "d", "e"}}},
+	}
+	for _, tt := range tests {
+		var pages [][]string
+		token := ""
+		for {
+			res, err := s.Search(context.Background(), &SearchQuery{Query: "attention", PageSize: tt.pageSize, PageToken: token})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.TotalSize != 6 {
+				t.Errorf("total_size = %d, want 6", res.TotalSize)
+			}
+			var page []string
+			for _, r := range res.Results {
+				page = append(page, r.Wrapup.Id)
+			}
+			pages = append(pages, page)
+			if res.NextPageToken == "" {
+				break
+			}
+			if len(pages) > len(ids)+1 {
+				t.Fatal("pagination does not end")
+			}
+			token = res.NextPageToken
+		}
+		if !reflect.DeepEqual(pages, tt.pages) {
+			t.Errorf("page size %d: pages = %v, want %v", tt.pageSize, pages, tt.pages)
+		}
+	}
+}
+
+func TestMemoryListPageToken(t *testing.T) {
+	s := newMemoryStore()
+	putWrapups(s,
+		&pb.Wrapup{Id: "a", Title: "B", CreateTime: &timestamp.Timestamp{Seconds: 100, Nanos: 1}},
+		&pb.Wrapup{Id: "b", Title: "A", CreateTime: &timestamp.Timestamp{Seconds: 100}},
+		&pb.Wrapup{Id: "c", Title: "A", CreateTime: &timestamp.Timestamp{Seconds: 200}},
+		&pb.Wrapup{Id: "d", Title: "C"},
+	)
+
+	tests := []struct {
+		orderBy []Order
+		want    []string
+	}{
+		{[]Order{{Field: "create_time"}}, []string{"d", "b", "a", "c"}},
+		{[]Order{{Field: "create_time", Descending: true}}, []string{"c", "a", "b", "d"}},
+		{[]Order{{Field: "title"}, {Field: "create_time", Descending: true}}, []string{"c", "b", "a", "d"}},
+	}
+	for _, tt := range tests {
+		// page size 1 makes every document go through page token
+		var ids []string
+		token := ""
+		for i := 0; i <= 4; i++ {
+			res, err := s.List(context.Background(), &ListQuery{OrderBy: tt.orderBy, PageSize: 1, PageToken: token})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, doc := range res.Wrapups {
+				ids = append(ids, doc.Id)
+			}
+			if token = res.NextPageToken; token == "" {
+				break
+			}
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("order by %v: %v, want %v", tt.orderBy, ids, tt.want)
+		}
+	}
+
+	if _, err := s.List(context.Background(), &ListQuery{PageSize: 1, PageToken: "invalid"}); err != ErrInvalidPageToken {
+		t.Errorf("err = %v for invalid token, want ErrInvalidPageToken", err)
+	}
+	res, err := s.List(context.Background(), &ListQuery{PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the token of other order has different number of sort values
+	_, err = s.List(context.Background(), &ListQuery{OrderBy: []Order{{Field: "create_time"}}, PageSize: 1, PageToken: res.NextPageToken})
+	if err != ErrInvalidPageToken {
+		t.Errorf("err = %v for token of other order, want ErrInvalidPageToken", err)
+	}
+}
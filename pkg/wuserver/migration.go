@@ -0,0 +1,544 @@
+package wuserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/olivere/elastic"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// reindexPollInterval is the interval of checking the progress of reindex task.
+	reindexPollInterval = 1 * time.Second
+	// backfillBatchSize is the number of documents updated at once by backfill.
+	backfillBatchSize = 500
+)
+
+// migration is a change of the settings or mapping of wrapup index.
+type migration struct {
+	// version is the schema version after the migration.
+	version     int
+	description string
+	// reindex reports whether the change cannot be applied to existing index,
+	// like changing the type of existing field or static settings.
+	// If true, documents are copied to new index with current schema.
+	// Otherwise, current mapping is put to existing index, which can only add new fields.
+	reindex bool
+	// script is the painless script to convert each document. Optional.
+	// It is run while reindexing, or by update_by_query on existing index after new fields are added.
+	script string
+	// backfill fills the fields of documents which cannot be computed by painless script. Optional.
+	// It is run on the index after documents are copied or new fields are added, so it must be idempotent.
+	backfill func(ctx context.Context, client *elastic.Client, index string) error
+}
+
+// migrations is the history of schema changes ordered by version.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "explicit settings and mapping, and versioned index behind alias",
+		// the index created before schema version was introduced may have dynamically mapped fields
+		// which conflict with explicit mapping, like tags as text
+		reindex: true,
+	},
+	{
+		version:     2,
+		description: "created_by and updated_by as keyword",
+	},
+	{
+		version:     3,
+		description: "id as keyword to sort without fielddata",
+		script:      "ctx._source.id = ctx._id;",
+	},
+	{
+		version:     4,
+		description: "normalized_title of the documents created before duplicate check",
+		backfill:    backfillNormalizedTitles,
+	},
+}
+
+// versionedIndexName returns the name of wrapup index of given schema version.
+func versionedIndexName(alias string, version int) string {
+	return fmt.Sprintf("%s-v%d", alias, version)
+}
+
+// migrateIndex makes alias point to the wrapup index with current schema version.
+// If no index exists, new index is created.
+// If the index has older schema version, pending migrations are applied.
+// The index created before alias was introduced, whose name is the same as alias, is also migrated and replaced with alias.
+func migrateIndex(ctx context.Context, client *elastic.Client, logger *zap.Logger, alias string) error {
+	current, err := aliasedIndex(ctx, client, alias)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		exists, err := client.IndexExists(alias).Do(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to check whether index exists")
+		}
+		if !exists {
+			index := versionedIndexName(alias, schemaVersion)
+			logger.Info(fmt.Sprintf("index \"%s\" not found. creating \"%s\"", alias, index))
+			if _, err := client.CreateIndex(index).BodyString(indexBody).Do(ctx); err != nil {
+				return errors.Wrapf(err, "failed to create index \"%s\"", index)
+			}
+			if _, err := client.Alias().Add(index, alias).Do(ctx); err != nil {
+				return errors.Wrapf(err, "failed to add alias \"%s\"", alias)
+			}
+			return nil
+		}
+		current = alias
+	}
+
+	version, err := indexSchemaVersion(ctx, client, current)
+	if err != nil {
+		return err
+	}
+	if version == schemaVersion {
+		return nil
+	}
+	if version > schemaVersion {
+		// older server must not write documents to the index it does not know
+		return errors.Errorf("schema version %d of index \"%s\" is newer than supported version %d", version, current, schemaVersion)
+	}
+
+	// the index without alias is always reindexed to be replaced with alias
+	reindex := current == alias
+	var scripts []string
+	var backfills []func(context.Context, *elastic.Client, string) error
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		logger.Info("applying migration", zap.Int("version", m.version), zap.String("description", m.description))
+		if m.reindex {
+			reindex = true
+		}
+		if m.script != "" {
+			scripts = append(scripts, m.script)
+		}
+		if m.backfill != nil {
+			backfills = append(backfills, m.backfill)
+		}
+	}
+	if !reindex {
+		if len(scripts) > 0 || len(backfills) > 0 {
+			// new fields are added with old schema version, so that the documents are converted again
+			// if it fails or the server stops before all documents are converted
+			if _, err := client.PutMapping().Index(current).Type(typ).BodyString(versionedIndexMapping(version)).Do(ctx); err != nil {
+				return errors.Wrapf(err, "failed to put mapping to index \"%s\"", current)
+			}
+		}
+		if len(scripts) > 0 {
+			if err := convertDocuments(ctx, client, current, strings.Join(scripts, "\n")); err != nil {
+				return err
+			}
+		}
+		for _, backfill := range backfills {
+			if err := backfill(ctx, client, current); err != nil {
+				return err
+			}
+		}
+		if _, err := client.PutMapping().Index(current).Type(typ).BodyString(indexMapping).Do(ctx); err != nil {
+			return errors.Wrapf(err, "failed to put mapping to index \"%s\"", current)
+		}
+		logger.Info(fmt.Sprintf("index \"%s\" migrated to schema version %d", current, schemaVersion))
+		return nil
+	}
+	job := &reindexJob{
+		client:    client,
+		logger:    logger,
+		alias:     alias,
+		source:    current,
+		target:    versionedIndexName(alias, schemaVersion),
+		script:    strings.Join(scripts, "\n"),
+		backfills: backfills,
+	}
+	return job.run(ctx)
+}
+
+// convertDocuments runs script on all documents in index by update_by_query.
+func convertDocuments(ctx context.Context, client *elastic.Client, index, script string) error {
+	res, err := client.UpdateByQuery(index).Script(elastic.NewScript(script)).Refresh("true").Do(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert documents in index \"%s\"", index)
+	}
+	if len(res.Failures) > 0 {
+		f := res.Failures[0]
+		return errors.Errorf("failed to convert %d documents in index \"%s\": ID %s failed with status %d",
+			len(res.Failures), index, f.Id, f.Status)
+	}
+	return nil
+}
+
+// backfillNormalizedTitles sets normalized_title of the documents created before it was stored.
+// This cannot be done by painless script because normalizeTitle depends on Unicode normalization.
+func backfillNormalizedTitles(ctx context.Context, client *elastic.Client, index string) error {
+	query := elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("normalized_title"))
+	source := elastic.NewFetchSourceContext(true).Include("title")
+	for {
+		// backfilled documents are refreshed, so each search returns the documents not backfilled yet
+		result, err := client.Search(index).Query(query).FetchSourceContext(source).Size(backfillBatchSize).Do(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to search documents in index \"%s\"", index)
+		}
+		if len(result.Hits.Hits) == 0 {
+			return nil
+		}
+		bulk := client.Bulk().Index(index).Type(typ).Refresh("true")
+		for _, hit := range result.Hits.Hits {
+			var doc struct {
+				Title string `json:"title"`
+			}
+			if err := json.Unmarshal(*hit.Source, &doc); err != nil {
+				return errors.Wrap(err, "failed to Unmarshal response to JSON")
+			}
+			update := map[string]string{"normalized_title": normalizeTitle(doc.Title)}
+			bulk = bulk.Add(elastic.NewBulkUpdateRequest().Id(hit.Id).Doc(update))
+		}
+		res, err := bulk.Do(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to backfill normalized_title in index \"%s\"", index)
+		}
+		if failed := res.Failed(); len(failed) > 0 {
+			reason := ""
+			if failed[0].Error != nil {
+				reason = failed[0].Error.Reason
+			}
+			return errors.Errorf("failed to backfill normalized_title of %d documents in index \"%s\": %s", len(failed), index, reason)
+		}
+	}
+}
+
+// aliasedIndex returns the index which alias points to.
+// Empty string is returned if alias does not exist.
+func aliasedIndex(ctx context.Context, client *elastic.Client, alias string) (string, error) {
+	res, err := client.Aliases().Alias(alias).Do(ctx)
+	if elastic.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get alias \"%s\"", alias)
+	}
+	indices := res.IndicesByAlias(alias)
+	switch len(indices) {
+	case 0:
+		return "", nil
+	case 1:
+		return indices[0], nil
+	}
+	return "", errors.Errorf("alias \"%s\" points to multiple indices %v", alias, indices)
+}
+
+// indexSchemaVersion returns the schema version stored in the mapping of index.
+// 0 is returned for the index created before schema version was introduced.
+func indexSchemaVersion(ctx context.Context, client *elastic.Client, index string) (int, error) {
+	res, err := client.GetMapping().Index(index).Type(typ).Do(ctx)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get mapping of index \"%s\"", index)
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to encode mapping")
+	}
+	var mappings map[string]struct {
+		Mappings map[string]struct {
+			Meta struct {
+				SchemaVersion int `json:"schema_version"`
+			} `json:"_meta"`
+		} `json:"mappings"`
+	}
+	if err := json.Unmarshal(b, &mappings); err != nil {
+		return 0, errors.Wrap(err, "failed to decode mapping")
+	}
+	return mappings[index].Mappings[typ].Meta.SchemaVersion, nil
+}
+
+// reindexJob copies all documents in source index to target index with current schema,
+// and switches alias to target index atomically.
+// If it fails before the alias is switched, target index is deleted and source index is left as is.
+type reindexJob struct {
+	client *elastic.Client
+	logger *zap.Logger
+	alias  string
+	source string
+	target string
+	// script converts each document if not empty.
+	script string
+	// backfills are run on target index after documents are copied.
+	backfills []func(context.Context, *elastic.Client, string) error
+	// deleteSource reports whether source index is deleted after the alias is switched.
+	// The index whose name is the same as alias is always deleted because the alias cannot be added otherwise.
+	deleteSource bool
+	// progress is called at each step if not nil.
+	progress func(*pb.ReindexProgress)
+}
+
+func (j *reindexJob) report(step pb.ReindexProgress_Step, total, copied int64) {
+	if j.progress == nil {
+		return
+	}
+	j.progress(&pb.ReindexProgress{
+		Step:        step,
+		SourceIndex: j.source,
+		TargetIndex: j.target,
+		Total:       total,
+		Copied:      copied,
+	})
+}
+
+func (j *reindexJob) run(ctx context.Context) error {
+	j.report(pb.ReindexProgress_CREATE_INDEX, 0, 0)
+	// the index may be left by the migration interrupted before, which the alias does not point to yet
+	exists, err := j.client.IndexExists(j.target).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to check whether index exists")
+	}
+	if exists {
+		j.logger.Warn(fmt.Sprintf("index \"%s\" already exists. recreating", j.target))
+		if _, err := j.client.DeleteIndex(j.target).Do(ctx); err != nil {
+			return errors.Wrapf(err, "failed to delete index \"%s\"", j.target)
+		}
+	}
+	if _, err := j.client.CreateIndex(j.target).BodyString(indexBody).Do(ctx); err != nil {
+		return errors.Wrapf(err, "failed to create index \"%s\"", j.target)
+	}
+
+	// block writes to source index so that no document written by other server instances is lost.
+	// those writes fail until the alias is switched.
+	if err := j.blockWrites(ctx, j.source, true); err != nil {
+		j.deleteTarget()
+		return err
+	}
+	total, err := j.copy(ctx)
+	if err != nil {
+		j.unblockSource()
+		j.deleteTarget()
+		return err
+	}
+	j.logger.Info(fmt.Sprintf("alias \"%s\" switched from \"%s\" to \"%s\"", j.alias, j.source, j.target), zap.Int64("documents", total))
+
+	switch {
+	case j.source == j.alias:
+		// the index was removed when the alias was switched
+	case j.deleteSource:
+		j.report(pb.ReindexProgress_DELETE_OLD_INDEX, total, total)
+		if _, err := j.client.DeleteIndex(j.source).Do(ctx); err != nil {
+			return errors.Wrapf(err, "failed to delete index \"%s\"", j.source)
+		}
+	default:
+		// old index is kept as it was
+		j.unblockSource()
+	}
+	j.report(pb.ReindexProgress_DONE, total, total)
+	return nil
+}
+
+// copy copies documents to target index and switches the alias, and returns the number of documents.
+func (j *reindexJob) copy(ctx context.Context) (int64, error) {
+	total, err := j.client.Count(j.source).Do(ctx)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to count documents in index \"%s\"", j.source)
+	}
+	j.report(pb.ReindexProgress_COPY, total, 0)
+	j.logger.Info(fmt.Sprintf("reindexing \"%s\" to \"%s\"", j.source, j.target), zap.Int64("documents", total))
+
+	reindex := j.client.Reindex().SourceIndex(j.source).DestinationIndex(j.target)
+	if j.script != "" {
+		reindex = reindex.Script(elastic.NewScript(j.script))
+	}
+	task, err := reindex.DoAsync(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to start reindex")
+	}
+	for {
+		res, err := getReindexTask(ctx, j.client, task.TaskId)
+		if err != nil {
+			return 0, err
+		}
+		copied := reindexedCount(res.Task)
+		j.report(pb.ReindexProgress_COPY, total, copied)
+		if res.Completed {
+			if err := res.err(); err != nil {
+				return 0, err
+			}
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(reindexPollInterval):
+		}
+	}
+	if _, err := j.client.Refresh(j.target).Do(ctx); err != nil {
+		return 0, errors.Wrapf(err, "failed to refresh index \"%s\"", j.target)
+	}
+	for _, backfill := range j.backfills {
+		if err := backfill(ctx, j.client, j.target); err != nil {
+			return 0, err
+		}
+	}
+
+	// documents missed without reported failures are detected by the number of documents
+	j.report(pb.ReindexProgress_VERIFY, total, total)
+	count, err := j.client.Count(j.target).Do(ctx)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to count documents in index \"%s\"", j.target)
+	}
+	if count != total {
+		return 0, errors.Errorf("the number of documents does not match after reindex: %d in \"%s\", %d in \"%s\"", total, j.source, count, j.target)
+	}
+
+	j.report(pb.ReindexProgress_SWITCH_ALIAS, total, total)
+	actions := []elastic.AliasAction{elastic.NewAliasAddAction(j.alias).Index(j.target)}
+	if j.source == j.alias {
+		// alias cannot be added while the index of the same name exists, so remove it in the same request
+		actions = append(actions, elastic.NewAliasRemoveIndexAction(j.source))
+	} else {
+		actions = append(actions, elastic.NewAliasRemoveAction(j.alias).Index(j.source))
+	}
+	if _, err := j.client.Alias().Action(actions...).Do(ctx); err != nil {
+		return 0, errors.Wrapf(err, "failed to switch alias \"%s\"", j.alias)
+	}
+	return total, nil
+}
+
+// blockWrites sets whether writes to index are blocked.
+func (j *reindexJob) blockWrites(ctx context.Context, index string, block bool) error {
+	settings := map[string]interface{}{"index.blocks.write": block}
+	if _, err := j.client.IndexPutSettings(index).BodyJson(settings).Do(ctx); err != nil {
+		return errors.Wrapf(err, "failed to set write block of index \"%s\"", index)
+	}
+	return nil
+}
+
+// unblockSource allows writes to source index again.
+// This uses new context because the context of request may be already canceled.
+func (j *reindexJob) unblockSource() {
+	if err := j.blockWrites(context.Background(), j.source, false); err != nil {
+		j.logger.Error(fmt.Sprintf("writes to index \"%s\" remain blocked. reset index.blocks.write manually", j.source), zap.Error(err))
+	}
+}
+
+// deleteTarget deletes target index after failure.
+// This uses new context because the context of request may be already canceled.
+func (j *reindexJob) deleteTarget() {
+	if _, err := j.client.DeleteIndex(j.target).Do(context.Background()); err != nil {
+		j.logger.Error(fmt.Sprintf("failed to delete index \"%s\"", j.target), zap.Error(err))
+	}
+}
+
+// reindexTask is the response of task API for reindex task.
+// TasksGetTaskResponse of the client does not have the result of completed task, so it is decoded directly.
+type reindexTask struct {
+	Completed bool              `json:"completed"`
+	Task      *elastic.TaskInfo `json:"task"`
+	// Response is the result of reindex set after the task completed.
+	Response *struct {
+		Failures []reindexFailure `json:"failures"`
+	} `json:"response"`
+	// Error is set if the task itself failed.
+	Error *elastic.ErrorDetails `json:"error"`
+}
+
+// reindexFailure is the document or the shard which reindex task failed to copy.
+type reindexFailure struct {
+	Index string `json:"index"`
+	ID    string `json:"id"`
+	// Cause is set when indexing the document failed, and Reason is set when searching the shard failed.
+	Cause  *elastic.ErrorDetails `json:"cause"`
+	Reason *elastic.ErrorDetails `json:"reason"`
+}
+
+// maxReportedFailures is the maximum number of failures whose reasons are contained in the error.
+const maxReportedFailures = 3
+
+// err returns the error with the reasons of failures of completed task.
+func (t *reindexTask) err() error {
+	if t.Error != nil {
+		return errors.Errorf("reindex failed: %s", t.Error.Reason)
+	}
+	if t.Response == nil || len(t.Response.Failures) == 0 {
+		return nil
+	}
+	var reasons []string
+	for i, f := range t.Response.Failures {
+		if i == maxReportedFailures {
+			break
+		}
+		switch {
+		case f.Cause != nil:
+			reasons = append(reasons, fmt.Sprintf("ID %s: %s", f.ID, f.Cause.Reason))
+		case f.Reason != nil:
+			reasons = append(reasons, fmt.Sprintf("index %s: %s", f.Index, f.Reason.Reason))
+		}
+	}
+	return errors.Errorf("reindex failed with %d failures: %s", len(t.Response.Failures), strings.Join(reasons, "; "))
+}
+
+// getReindexTask returns the status of reindex task which has given ID.
+func getReindexTask(ctx context.Context, client *elastic.Client, id string) (*reindexTask, error) {
+	res, err := client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: "GET",
+		Path:   "/_tasks/" + url.PathEscape(id),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get reindex task")
+	}
+	var task reindexTask
+	if err := json.Unmarshal(res.Body, &task); err != nil {
+		return nil, errors.Wrap(err, "failed to decode reindex task")
+	}
+	return &task, nil
+}
+
+// reindexedCount returns the number of documents written by reindex task so far.
+func reindexedCount(task *elastic.TaskInfo) int64 {
+	if task == nil {
+		return 0
+	}
+	status, ok := task.Status.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	var count int64
+	for _, key := range []string{"created", "updated"} {
+		if n, ok := status[key].(float64); ok {
+			count += int64(n)
+		}
+	}
+	return count
+}
+
+// Reindex copies all wrapup documents to new index with current schema and switches the alias to it.
+// Writes by this server are held until the alias is switched, and writes by other server instances
+// sharing the index fail while documents are copied because writes to old index are blocked.
+func (s *elasticStore) Reindex(ctx context.Context, deleteOld bool, progress func(*pb.ReindexProgress)) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	source, err := aliasedIndex(ctx, s.client, s.index)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		return errors.Errorf("alias \"%s\" does not exist", s.index)
+	}
+	job := &reindexJob{
+		client:       s.client,
+		logger:       s.logger,
+		alias:        s.index,
+		source:       source,
+		target:       fmt.Sprintf("%s-%d", versionedIndexName(s.index, schemaVersion), time.Now().Unix()),
+		deleteSource: deleteOld,
+		progress:     progress,
+	}
+	return job.run(ctx)
+}
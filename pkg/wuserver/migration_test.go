@@ -0,0 +1,61 @@
+package wuserver
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReindexTaskErr(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "succeeded",
+			body: `{"completed": true, "response": {"created": 2, "failures": []}}`,
+		},
+		{
+			name: "running",
+			body: `{"completed": false, "task": {"status": {"created": 1}}}`,
+		},
+		{
+			name: "document failures",
+			body: `{"completed": true, "response": {"failures": [
+				{"index": "wrapups-v4", "id": "a", "status": 400, "cause": {"type": "mapper_parsing_exception", "reason": "failed to parse field [year]"}},
+				{"index": "wrapups-v4", "id": "b", "status": 400, "cause": {"type": "mapper_parsing_exception", "reason": "failed to parse field [tags]"}},
+				{"index": "wrapups-v4", "id": "c", "status": 400, "cause": {"type": "mapper_parsing_exception", "reason": "c"}},
+				{"index": "wrapups-v4", "id": "d", "status": 400, "cause": {"type": "mapper_parsing_exception", "reason": "d"}}
+			]}}`,
+			want: "reindex failed with 4 failures: ID a: failed to parse field [year]; ID b: failed to parse field [tags]; ID c: c",
+		},
+		{
+			name: "search failure",
+			body: `{"completed": true, "response": {"failures": [{"index": "wrapups-v3", "shard": 0, "reason": {"type": "script_exception", "reason": "runtime error"}}]}}`,
+			want: "reindex failed with 1 failures: index wrapups-v3: runtime error",
+		},
+		{
+			name: "task error",
+			body: `{"completed": true, "error": {"type": "index_not_found_exception", "reason": "no such index"}}`,
+			want: "reindex failed: no such index",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var task reindexTask
+			if err := json.Unmarshal([]byte(tt.body), &task); err != nil {
+				t.Fatal(err)
+			}
+			err := task.err()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
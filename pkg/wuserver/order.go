@@ -0,0 +1,66 @@
+package wuserver
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// orderFields is the set of field names which can be used in order_by.
+var orderFields = map[string]bool{
+	"create_time": true,
+	"title":       true,
+	"relevance":   true,
+}
+
+// defaultOrderBy is the order used when order_by is not specified.
+const defaultOrderBy = "relevance desc, create_time desc"
+
+// Order is one clause of parsed order_by.
+type Order struct {
+	// Field is one of "create_time", "title" and "relevance".
+	Field string
+	// Descending is true if the documents are sorted in descending order.
+	Descending bool
+}
+
+// parseOrderBy parses order_by into the list of orders.
+//
+// order_by is comma-separated list of field name optionally followed by "asc" or "desc" like "create_time desc, title".
+// Default direction is ascending, except relevance which is descending by default.
+func parseOrderBy(orderBy string) ([]Order, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		orderBy = defaultOrderBy
+	}
+
+	orders := make([]Order, 0, len(orderFields))
+	seen := make(map[string]bool, len(orderFields))
+	for _, clause := range strings.Split(orderBy, ",") {
+		words := strings.Fields(clause)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, errors.Errorf("invalid order_by clause \"%s\"", strings.TrimSpace(clause))
+		}
+		field := words[0]
+		if !orderFields[field] {
+			return nil, errors.Errorf("unknown order_by field \"%s\"", field)
+		}
+		if seen[field] {
+			return nil, errors.Errorf("order_by field \"%s\" is specified more than once", field)
+		}
+		seen[field] = true
+
+		descending := field == "relevance"
+		if len(words) == 2 {
+			switch strings.ToLower(words[1]) {
+			case "asc":
+				descending = false
+			case "desc":
+				descending = true
+			default:
+				return nil, errors.Errorf("invalid order direction \"%s\"", words[1])
+			}
+		}
+		orders = append(orders, Order{Field: field, Descending: descending})
+	}
+	return orders, nil
+}
@@ -0,0 +1,109 @@
+package wuserver
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+func TestParseOrderBy(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    []Order
+		wantErr string
+	}{
+		{"", []Order{{Field: "relevance", Descending: true}, {Field: "create_time", Descending: true}}, ""},
+		{"  ", []Order{{Field: "relevance", Descending: true}, {Field: "create_time", Descending: true}}, ""},
+		{"title", []Order{{Field: "title"}}, ""},
+		{"create_time desc", []Order{{Field: "create_time", Descending: true}}, ""},
+		{"create_time DESC", []Order{{Field: "create_time", Descending: true}}, ""},
+		{"relevance", []Order{{Field: "relevance", Descending: true}}, ""},
+		{"relevance asc", []Order{{Field: "relevance"}}, ""},
+		{"title asc, create_time desc", []Order{{Field: "title"}, {Field: "create_time", Descending: true}}, ""},
+		{" create_time ,title desc,relevance ", []Order{{Field: "create_time"}, {Field: "title", Descending: true}, {Field: "relevance", Descending: true}}, ""},
+		{"id", nil, "unknown order_by field \"id\""},
+		{"year desc", nil, "unknown order_by field \"year\""},
+		{"title descending", nil, "invalid order direction \"descending\""},
+		{"title asc desc", nil, "invalid order_by clause \"title asc desc\""},
+		{"title,", nil, "invalid order_by clause \"\""},
+		{"title, title desc", nil, "order_by field \"title\" is specified more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.orderBy, func(t *testing.T) {
+			got, err := parseOrderBy(tt.orderBy)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseOrderBy(%q) = %v, want %v", tt.orderBy, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOrderByIDTiebreaker checks that the documents having the same sort values are ordered by ID,
+// so that paging does not skip or repeat them.
+func TestOrderByIDTiebreaker(t *testing.T) {
+	s := newMemoryStore()
+	putWrapups(s,
+		&pb.Wrapup{Id: "c", Title: "A", CreateTime: &timestamp.Timestamp{Seconds: 100}},
+		&pb.Wrapup{Id: "a", Title: "A", CreateTime: &timestamp.Timestamp{Seconds: 100}},
+		&pb.Wrapup{Id: "b", Title: "A", CreateTime: &timestamp.Timestamp{Seconds: 100}},
+	)
+	tests := []struct {
+		orderBy string
+		want    []string
+	}{
+		{"title", []string{"a", "b", "c"}},
+		// ID is ascending even if the other fields are descending
+		{"title desc, create_time desc", []string{"a", "b", "c"}},
+		{"relevance", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.orderBy, func(t *testing.T) {
+			orders := mustParseOrderBy(t, tt.orderBy)
+			var ids []string
+			token := ""
+			for i := 0; i < 4; i++ {
+				res, err := s.List(context.Background(), &ListQuery{OrderBy: orders, PageSize: 1, PageToken: token})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, doc := range res.Wrapups {
+					ids = append(ids, doc.Id)
+				}
+				if token = res.NextPageToken; token == "" {
+					break
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ids = %v, want %v", ids, tt.want)
+			}
+
+			// Elasticsearch also sorts by ID at last
+			sorters := esSorters(orders)
+			source, err := sorters[len(sorters)-1].Source()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(b); !strings.Contains(got, `"id":{"order":"asc"}`) {
+				t.Errorf("last sorter = %s, want id in ascending order", got)
+			}
+		})
+	}
+}
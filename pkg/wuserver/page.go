@@ -0,0 +1,54 @@
+package wuserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultPageSize is the page size used when the request does not specify it.
+	defaultPageSize = 50
+	// maxPageSize is the maximum page size. larger page size is coerced to this value.
+	maxPageSize = 1000
+)
+
+// pageSize returns the page size to use for the requested size.
+func pageSize(size int32) int {
+	if size <= 0 {
+		return defaultPageSize
+	}
+	if size > maxPageSize {
+		return maxPageSize
+	}
+	return int(size)
+}
+
+// encodePageToken encodes the sort values of the last hit into opaque page token.
+func encodePageToken(sortValues []interface{}) (string, error) {
+	b, err := json.Marshal(sortValues)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode page token")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodePageToken decodes page token into the sort values used for search_after.
+func decodePageToken(token string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed page token")
+	}
+	var sortValues []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&sortValues); err != nil {
+		return nil, errors.Wrap(err, "malformed page token")
+	}
+	if len(sortValues) == 0 {
+		return nil, errors.New("malformed page token")
+	}
+	return sortValues, nil
+}
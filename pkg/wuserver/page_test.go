@@ -0,0 +1,167 @@
+package wuserver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	tests := [][]interface{}{
+		{"id"},
+		{int64(1546300800), int64(123), "id"},
+		{"Attention Is All You Need", 1.5, "id"},
+	}
+	for _, values := range tests {
+		token, err := encodePageToken(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodePageToken(token)
+		if err != nil {
+			t.Fatalf("decodePageToken(%q) for %v: %v", token, values, err)
+		}
+		// numbers are decoded as json.Number so that large integers keep their precision
+		want := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			if _, ok := v.(string); !ok {
+				v = json.Number(fmt.Sprint(v))
+			}
+			want = append(want, v)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("decoded %v, want %v", got, want)
+		}
+	}
+}
+
+func TestDecodePageTokenInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "invalid token!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`["abc"]`))},
+		{"not JSON", encode("id")},
+		{"truncated JSON", encode(`["id"`)},
+		{"object", encode(`{"id": "a"}`)},
+		{"null", encode("null")},
+		{"empty array", encode("[]")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if values, err := decodePageToken(tt.token); err == nil {
+				t.Errorf("decodePageToken(%q) = %v, want error", tt.token, values)
+			}
+		})
+	}
+}
+
+func TestListWrapupsPageToken(t *testing.T) {
+	store := newMemoryStore()
+	putWrapups(store,
+		&pb.Wrapup{Id: "a", Title: "A", CreateTime: &timestamp.Timestamp{Seconds: 400}},
+		&pb.Wrapup{Id: "b", Title: "B", CreateTime: &timestamp.Timestamp{Seconds: 300}},
+		&pb.Wrapup{Id: "c", Title: "C", CreateTime: &timestamp.Timestamp{Seconds: 200}},
+		&pb.Wrapup{Id: "d", Title: "D", CreateTime: &timestamp.Timestamp{Seconds: 100}},
+	)
+	s := &WrapupsServer{store: store, logger: zap.NewNop(), titleLocks: newTitleLocks()}
+	ctx := context.Background()
+
+	// list returns the pages until next_page_token becomes empty
+	pages := func(pageSize int32) [][]string {
+		var pages [][]string
+		token := ""
+		for i := 0; i < 5; i++ {
+			res, err := s.ListWrapups(ctx, &pb.ListWrapupsRequest{OrderBy: "title", PageSize: pageSize, PageToken: token})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, doc := range res.Wrapups {
+				ids = append(ids, doc.Id)
+			}
+			pages = append(pages, ids)
+			if token = res.NextPageToken; token == "" {
+				return pages
+			}
+		}
+		t.Fatalf("page size %d: next_page_token is not empty after %v", pageSize, pages)
+		return nil
+	}
+	sizeTests := []struct {
+		pageSize int32
+		want     [][]string
+	}{
+		// the last page is full, but next_page_token must be empty
+		{2, [][]string{{"a", "b"}, {"c", "d"}}},
+		{3, [][]string{{"a", "b", "c"}, {"d"}}},
+		{4, [][]string{{"a", "b", "c", "d"}}},
+		{10, [][]string{{"a", "b", "c", "d"}}},
+	}
+	for _, tt := range sizeTests {
+		if got := pages(tt.pageSize); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("page size %d: pages = %v, want %v", tt.pageSize, got, tt.want)
+		}
+	}
+
+	res, err := s.ListWrapups(ctx, &pb.ListWrapupsRequest{OrderBy: "title", PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := decodePageToken(res.NextPageToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamper := func(values ...interface{}) string {
+		token, err := encodePageToken(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	tokenTests := []struct {
+		name    string
+		orderBy string
+		token   string
+	}{
+		{"malformed", "title", "invalid"},
+		{"extra value", "title", tamper(append(values, "x")...)},
+		{"missing value", "title", tamper(values[1:]...)},
+		{"number instead of string", "title", tamper(1, values[1])},
+		{"object value", "title", tamper(map[string]string{"title": "A"}, values[1])},
+		{"token of other order", "create_time", res.NextPageToken},
+	}
+	for _, tt := range tokenTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ListWrapups(ctx, &pb.ListWrapupsRequest{OrderBy: tt.orderBy, PageSize: 1, PageToken: tt.token})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("err = %v, want InvalidArgument", err)
+			}
+			if _, err := store.List(ctx, &ListQuery{OrderBy: mustParseOrderBy(t, tt.orderBy), PageSize: 1, PageToken: tt.token}); err != ErrInvalidPageToken {
+				t.Errorf("store returns %v, want ErrInvalidPageToken", err)
+			}
+		})
+	}
+}
+
+func mustParseOrderBy(t *testing.T, orderBy string) []Order {
+	t.Helper()
+	orders, err := parseOrderBy(orderBy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return orders
+}
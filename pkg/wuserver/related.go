@@ -0,0 +1,53 @@
+package wuserver
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultRelatedSize is the number of related wrapups returned when not specified.
+	defaultRelatedSize = 10
+	// maxRelatedSize is the maximum number of related wrapups.
+	maxRelatedSize = 100
+)
+
+// relatedFields is the text fields compared by FindRelatedWrapups.
+var relatedFields = []string{"title", "wrapup", "comment", "note"}
+
+// FindRelatedWrapups returns wrapup documents which are similar to the given one.
+// The given document itself and wrapup documents in the trash are not included.
+func (s *WrapupsServer) FindRelatedWrapups(ctx context.Context, req *pb.FindRelatedWrapupsRequest) (*pb.FindRelatedWrapupsResponse, error) {
+	if req.Id == "" {
+		errMsg := "Id is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	doc, err := s.getWrapup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if doc.DeleteTime != nil {
+		errMsg := fmt.Sprintf("ID %s not found", req.Id)
+		return nil, status.Error(codes.NotFound, errMsg)
+	}
+
+	size := int(req.Size)
+	if size <= 0 {
+		size = defaultRelatedSize
+	} else if size > maxRelatedSize {
+		size = maxRelatedSize
+	}
+
+	related, err := s.store.Related(ctx, req.Id, size)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to search related documents")
+	}
+	return &pb.FindRelatedWrapupsResponse{
+		Related: related,
+	}, nil
+}
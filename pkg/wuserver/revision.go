@@ -0,0 +1,116 @@
+package wuserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mas9612/wrapups/pkg/auth"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxRevisions is the maximum number of revisions returned by ListWrapupRevisions.
+const maxRevisions = 1000
+
+// ListWrapupRevisions returns the list of prior revisions of a wrapup document.
+func (s *WrapupsServer) ListWrapupRevisions(ctx context.Context, req *pb.ListWrapupRevisionsRequest) (*pb.ListWrapupRevisionsResponse, error) {
+	if req.Id == "" {
+		errMsg := "Id is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	if _, err := s.getWrapup(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.store.ListRevisions(ctx, req.Id)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to get revisions")
+	}
+	return &pb.ListWrapupRevisionsResponse{
+		Count:     int32(len(revisions)),
+		Revisions: revisions,
+	}, nil
+}
+
+// GetWrapupRevision returns a prior revision of a wrapup document.
+func (s *WrapupsServer) GetWrapupRevision(ctx context.Context, req *pb.GetWrapupRevisionRequest) (*pb.WrapupRevision, error) {
+	if req.Id == "" {
+		errMsg := "Id is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	if req.Revision <= 0 {
+		errMsg := "Revision must be greater than 0"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	return s.getRevision(ctx, req.Id, req.Revision)
+}
+
+// RollbackWrapup restores the contents of a wrapup document to the specified revision.
+// The contents before rollback are also recorded as a new revision.
+func (s *WrapupsServer) RollbackWrapup(ctx context.Context, req *pb.RollbackWrapupRequest) (*pb.Wrapup, error) {
+	if req.Id == "" {
+		errMsg := "Id is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	if req.Revision <= 0 {
+		errMsg := "Revision must be greater than 0"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	current, err := s.getWrapup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if current.DeleteTime != nil {
+		errMsg := fmt.Sprintf("ID %s not found", req.Id)
+		return nil, status.Error(codes.NotFound, errMsg)
+	}
+	revision, err := s.getRevision(ctx, req.Id, req.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := proto.Clone(current).(*pb.Wrapup)
+	for _, path := range updatableFields {
+		applyField(updated, revision.Wrapup, path)
+	}
+	updated.UpdateTime = ptypes.TimestampNow()
+	updated.UpdatedBy, _ = auth.UserFromContext(ctx)
+
+	doc, err := s.updateWrapup(ctx, updated)
+	if err != nil {
+		return nil, err
+	}
+	s.saveRevision(ctx, current)
+	return doc, nil
+}
+
+// getRevision returns the revision of the wrapup document which has given ID.
+// Returned error is already converted to gRPC status.
+func (s *WrapupsServer) getRevision(ctx context.Context, id string, revision int32) (*pb.WrapupRevision, error) {
+	doc, err := s.store.GetRevision(ctx, id, revision)
+	if err != nil {
+		return nil, s.storeError(err, fmt.Sprintf("revision %d of ID %s not found", revision, id), "failed to get revision")
+	}
+	return doc, nil
+}
+
+// saveRevision stores wrapup, which is the contents before change, as a revision.
+// This method must be called after the wrapup document is changed successfully,
+// so that no revision is left for the change which did not happen.
+// The revision number is the version of wrapup, which only one successful change can replace.
+// Failure is only logged because the change itself has been made.
+func (s *WrapupsServer) saveRevision(ctx context.Context, wrapup *pb.Wrapup) {
+	if err := s.store.SaveRevision(ctx, wrapup); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to save revision %d of ID %s", wrapup.Version, wrapup.Id), zap.Error(err))
+	}
+}
@@ -0,0 +1,85 @@
+package wuserver
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// revisionNumbers returns the revision numbers of the wrapup document which has given ID.
+func revisionNumbers(t *testing.T, s Store, id string) []int32 {
+	t.Helper()
+	revisions, err := s.ListRevisions(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []int32
+	for _, revision := range revisions {
+		numbers = append(numbers, revision.Revision)
+	}
+	return numbers
+}
+
+func TestUpdateWrapupRevisions(t *testing.T) {
+	ctx := context.Background()
+	s := &WrapupsServer{store: newMemoryStore(), logger: zap.NewNop(), titleLocks: newTitleLocks()}
+	doc, err := s.store.Create(ctx, &pb.Wrapup{Title: "Attention Is All You Need"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := s.UpdateWrapup(ctx, &pb.UpdateWrapupRequest{Wrapup: &pb.Wrapup{Id: doc.Id, Title: doc.Title, Wrapup: "first", Version: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := revisionNumbers(t, s.store, doc.Id); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("revisions = %v after update, want [1]", got)
+	}
+
+	// the update with stale version fails without recording revision
+	_, err = s.UpdateWrapup(ctx, &pb.UpdateWrapupRequest{Wrapup: &pb.Wrapup{Id: doc.Id, Title: doc.Title, Wrapup: "stale", Version: 1}})
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("err = %v, want Aborted", err)
+	}
+	if got := revisionNumbers(t, s.store, doc.Id); len(got) != 1 {
+		t.Errorf("revisions = %v after failed update, want [1]", got)
+	}
+
+	// deletion records no revision, so the revision number skips its version
+	if _, err := s.DeleteWrapup(ctx, &pb.DeleteWrapupRequest{Id: doc.Id}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UndeleteWrapup(ctx, &pb.UndeleteWrapupRequest{Id: doc.Id}); err != nil {
+		t.Fatal(err)
+	}
+	rolledBack, err := s.RollbackWrapup(ctx, &pb.RollbackWrapupRequest{Id: doc.Id, Revision: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolledBack.Wrapup != "" || rolledBack.Version != first.Version+3 {
+		t.Errorf("rolled back to %q at version %d, want empty at version %d", rolledBack.Wrapup, rolledBack.Version, first.Version+3)
+	}
+	got := revisionNumbers(t, s.store, doc.Id)
+	if len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Fatalf("revisions = %v after rollback, want [1 4]", got)
+	}
+	revision, err := s.GetWrapupRevision(ctx, &pb.GetWrapupRevisionRequest{Id: doc.Id, Revision: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Wrapup.Wrapup != "first" {
+		t.Errorf("revision 4 has %q, want the contents before rollback", revision.Wrapup.Wrapup)
+	}
+	if _, err := s.GetWrapupRevision(ctx, &pb.GetWrapupRevisionRequest{Id: doc.Id, Revision: 2}); status.Code(err) != codes.NotFound {
+		t.Errorf("err = %v for skipped revision, want NotFound", err)
+	}
+
+	// saving the same revision again conflicts
+	if err := s.store.SaveRevision(ctx, doc); err != ErrConflict {
+		t.Errorf("err = %v for existing revision, want ErrConflict", err)
+	}
+}
@@ -0,0 +1,60 @@
+package wuserver
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// highlightFragmentSize is the maximum number of characters of one highlighted fragment.
+	highlightFragmentSize = 150
+	// highlightFragments is the maximum number of highlighted fragments per field.
+	highlightFragments = 3
+)
+
+// searchFields is the text fields searched by SearchWrapups with their boosts.
+var searchFields = map[string]float64{
+	"title":             3,
+	"metadata.authors":  2,
+	"wrapup":            2,
+	"metadata.abstract": 1.5,
+	"comment":           1,
+	"note":              1,
+}
+
+// SearchWrapups runs full-text search across all text fields of wrapup documents.
+// Results are ordered by relevance and include highlighted fragments.
+// Wrapup documents in the trash are not included.
+func (s *WrapupsServer) SearchWrapups(ctx context.Context, req *pb.SearchWrapupsRequest) (*pb.SearchWrapupsResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		errMsg := "Query is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	query := &SearchQuery{
+		Query:     req.Query,
+		PageSize:  pageSize(req.PageSize),
+		PageToken: req.PageToken,
+	}
+	res, err := s.store.Search(ctx, query)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to search documents")
+	}
+	return res, nil
+}
+
+// sortHighlightFields sorts the highlighted fields by the boost of field.
+func sortHighlightFields(fields []string) {
+	sort.Slice(fields, func(i, j int) bool {
+		if searchFields[fields[i]] != searchFields[fields[j]] {
+			return searchFields[fields[i]] > searchFields[fields[j]]
+		}
+		return fields[i] < fields[j]
+	})
+}
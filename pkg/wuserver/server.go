@@ -2,14 +2,15 @@ package wuserver
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/timestamp"
-	debuglogger "github.com/mas9612/wrapups/pkg/logger"
+	"github.com/mas9612/wrapups/pkg/auth"
 	pb "github.com/mas9612/wrapups/pkg/wrapups"
-	"github.com/olivere/elastic"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -17,23 +18,44 @@ import (
 )
 
 const (
-	defaultIndexName = "wrapups"
-	typ              = "_doc"
+	internalErrorMsg    = "internal server error occured. please try again later."
+	unavailableErrorMsg = "storage backend is temporarily unavailable. please try again later."
 
-	internalErrorMsg = "internal server error occured. please try again later."
+	// purgeInterval is the interval of checking whether deleted documents should be purged.
+	purgeInterval = 1 * time.Hour
 )
 
 // WrapupsServer is the implementation of pb.WrapupsServer.
+// WrapupsServer also implements pb.WrapupsAdminServer.
 type WrapupsServer struct {
-	client *elastic.Client
-	index  string
-	logger *zap.Logger
+	store      Store
+	logger     *zap.Logger
+	admins     map[string]bool
+	titleLocks *titleLocks
 }
 
 type config struct {
-	url   string
-	port  int
-	trace bool
+	url                 string
+	port                int
+	scheme              string
+	urls                []string
+	username            string
+	password            string
+	apiKey              string
+	caFile              string
+	certFile            string
+	keyFile             string
+	sniff               bool
+	sniffInterval       time.Duration
+	healthcheck         bool
+	healthcheckInterval time.Duration
+	retryMaxWait        time.Duration
+	startupTimeout      time.Duration
+	trace               bool
+	purgePeriod         time.Duration
+	dataDir             string
+	store               Store
+	admins              []string
 }
 
 // Option is wrapups server option.
@@ -55,6 +77,96 @@ func SetPort(port int) Option {
 	}
 }
 
+// SetScheme sets the scheme of Elasticsearch server url, "http" or "https".
+// Default is http.
+func SetScheme(scheme string) Option {
+	return func(c *config) {
+		c.scheme = scheme
+	}
+}
+
+// SetURLs sets the urls of Elasticsearch nodes like "https://es1:9200".
+// If set, SetURL, SetPort and SetScheme are ignored.
+func SetURLs(urls ...string) Option {
+	return func(c *config) {
+		c.urls = urls
+	}
+}
+
+// SetBasicAuth sets the username and password to authenticate to Elasticsearch.
+// This cannot be used with SetAPIKey.
+func SetBasicAuth(username, password string) Option {
+	return func(c *config) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// SetAPIKey sets the API key to authenticate to Elasticsearch.
+// The key must be the base64 encoded "id:api_key" which is returned as "encoded" by create API key API.
+// This cannot be used with SetBasicAuth.
+func SetAPIKey(key string) Option {
+	return func(c *config) {
+		c.apiKey = key
+	}
+}
+
+// SetCACert sets the file of PEM encoded CA certificates to verify Elasticsearch server certificate.
+// Default is the system CA certificates.
+func SetCACert(file string) Option {
+	return func(c *config) {
+		c.caFile = file
+	}
+}
+
+// SetClientCert sets the files of PEM encoded client certificate and its private key
+// to authenticate to Elasticsearch.
+func SetClientCert(certFile, keyFile string) Option {
+	return func(c *config) {
+		c.certFile = certFile
+		c.keyFile = keyFile
+	}
+}
+
+// SetSniff sets whether the client finds all nodes of Elasticsearch cluster by sniffing,
+// and the interval of sniffing. If interval is 0, default of the client is used.
+// Default is false.
+func SetSniff(enabled bool, interval time.Duration) Option {
+	return func(c *config) {
+		c.sniff = enabled
+		c.sniffInterval = interval
+	}
+}
+
+// SetHealthcheck sets whether the client checks health of Elasticsearch nodes periodically,
+// and the interval of checking. If interval is 0, default of the client is used.
+// Default is true.
+func SetHealthcheck(enabled bool, interval time.Duration) Option {
+	return func(c *config) {
+		c.healthcheck = enabled
+		c.healthcheckInterval = interval
+	}
+}
+
+// SetRetry sets the maximum wait between retries of the request to Elasticsearch.
+// The request failed by temporary unavailability of Elasticsearch is retried with exponential backoff
+// until the next wait exceeds maxWait. If maxWait is 0, requests are not retried.
+// Default is 5 seconds.
+func SetRetry(maxWait time.Duration) Option {
+	return func(c *config) {
+		c.retryMaxWait = maxWait
+	}
+}
+
+// SetStartupTimeout sets how long NewWrapupsServer waits for Elasticsearch to be available.
+// If timeout is 0, NewWrapupsServer fails immediately when Elasticsearch is unavailable.
+// Default is 0, while wuserver command waits 1 minute by default (--elastic-startup-timeout).
+func SetStartupTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.startupTimeout = timeout
+	}
+}
+
 // SetTrace sets whether trace log is enabled.
 // Default is false.
 func SetTrace(trace bool) Option {
@@ -63,98 +175,190 @@ func SetTrace(trace bool) Option {
 	}
 }
 
+// SetPurgePeriod sets the period after which deleted wrapups are purged permanently.
+// If period is 0, deleted wrapups are never purged.
+// Default is 30 days.
+func SetPurgePeriod(period time.Duration) Option {
+	return func(c *config) {
+		c.purgePeriod = period
+	}
+}
+
+// SetDataDir sets the local data directory.
+// If set, wrapups are persisted to the directory instead of Elasticsearch,
+// and Elasticsearch options are ignored.
+func SetDataDir(dir string) Option {
+	return func(c *config) {
+		c.dataDir = dir
+	}
+}
+
+// SetStore sets the storage backend.
+// If set, Elasticsearch options and data directory are ignored.
+// Default is Elasticsearch.
+func SetStore(store Store) Option {
+	return func(c *config) {
+		c.store = store
+	}
+}
+
+// SetAdmins sets the users allowed to call the RPCs of WrapupsAdmin service.
+// Default is empty, which means nobody can call them.
+func SetAdmins(users []string) Option {
+	return func(c *config) {
+		c.admins = users
+	}
+}
+
 // NewWrapupsServer creates and returns new WrapupsServer instance.
-// This method also create index for Elasticsearch if necessary.
-func NewWrapupsServer(logger *zap.Logger, opts ...Option) (pb.WrapupsServer, error) {
+// If storage backend is not set, this method loads the data directory if set,
+// or connects to Elasticsearch and create index if necessary.
+func NewWrapupsServer(logger *zap.Logger, opts ...Option) (*WrapupsServer, error) {
 	c := config{
-		url:   "localhost",
-		port:  9200,
-		trace: false,
+		url:          "localhost",
+		port:         9200,
+		scheme:       "http",
+		healthcheck:  true,
+		retryMaxWait: 5 * time.Second,
+		trace:        false,
+		purgePeriod:  30 * 24 * time.Hour,
 	}
 	for _, o := range opts {
 		o(&c)
 	}
 
 	wuServer := &WrapupsServer{
-		logger: logger,
+		store:      c.store,
+		logger:     logger,
+		admins:     make(map[string]bool, len(c.admins)),
+		titleLocks: newTitleLocks(),
 	}
-
-	logger.Info("initializing Elasticsearch client")
-	options := make([]elastic.ClientOptionFunc, 0, 10)
-	options = append(options, elastic.SetSniff(false))
-	if c.url != "localhost" || c.port != 9200 {
-		options = append(options, elastic.SetURL(fmt.Sprintf("http://%s:%d", c.url, c.port)))
+	for _, user := range c.admins {
+		wuServer.admins[user] = true
 	}
-	if c.trace {
-		l := &debuglogger.Logger{
-			Logger: logger,
+	if wuServer.store == nil && c.dataDir != "" {
+		store, err := newFileStore(logger, c.dataDir)
+		if err != nil {
+			return nil, err
 		}
-		options = append(options, elastic.SetTraceLog(l))
-	}
-	client, err := elastic.NewClient(options...)
-	if err != nil {
-		errMsg := "failed to initialize Elasticsearch client"
-		logger.Error(errMsg, zap.Error(err))
-		return nil, errors.Wrap(err, errMsg)
-	}
-
-	exists, err := client.IndexExists(defaultIndexName).Do(context.Background())
-	if err != nil {
-		errMsg := "failed to check whether index exists"
-		logger.Error(errMsg, zap.Error(err))
-		return nil, errors.Wrap(err, errMsg)
+		wuServer.store = store
 	}
-	if !exists {
-		logger.Info(fmt.Sprintf("index \"%s\" not found. creating", defaultIndexName))
-		_, err := client.CreateIndex(defaultIndexName).Do(context.Background())
+	if wuServer.store == nil {
+		store, err := newElasticStore(logger, &c)
 		if err != nil {
-			errMsg := fmt.Sprintf("failed to create index \"%s\"", defaultIndexName)
-			logger.Error(errMsg, zap.Error(err))
-			return nil, errors.Wrapf(err, errMsg)
+			return nil, err
 		}
+		wuServer.store = store
 	}
 
-	wuServer.client = client
-	wuServer.index = defaultIndexName
+	if c.purgePeriod > 0 {
+		go wuServer.purgeDeletedWrapups(c.purgePeriod)
+	}
 	logger.Info("server initialization finished")
 
 	return wuServer, nil
 }
 
-// ListWrapups returns the list of wrapup document stored in Elasticsearch.
-func (s *WrapupsServer) ListWrapups(ctx context.Context, req *pb.ListWrapupsRequest) (*pb.ListWrapupsResponse, error) {
-	var query elastic.Query
-	if req.Filter == "" {
-		query = elastic.NewMatchAllQuery()
-	} else {
-		query = elastic.NewMatchQuery("wrapup", req.Filter)
+// storeError converts the error returned by Store to gRPC status.
+// ErrNotFound is converted to NotFound with notFoundMsg,
+// temporary unavailability is logged with errMsg and converted to Unavailable,
+// and unknown errors are logged with errMsg and converted to Internal.
+func (s *WrapupsServer) storeError(err error, notFoundMsg, errMsg string) error {
+	switch errors.Cause(err) {
+	case ErrNotFound:
+		return status.Error(codes.NotFound, notFoundMsg)
+	case ErrInvalidPageToken:
+		s.logger.Error("invalid page token", zap.Error(err))
+		return status.Error(codes.InvalidArgument, "invalid page token")
 	}
-	result, err := s.client.Search(s.index).Query(query).Do(ctx)
-	if err != nil {
-		errMsg := "failed to get documents from Elasticsearch"
+	if isUnavailable(err) {
 		s.logger.Error(errMsg, zap.Error(err))
-		return nil, status.Error(codes.Internal, internalErrorMsg)
+		return status.Error(codes.Unavailable, unavailableErrorMsg)
 	}
+	s.logger.Error(errMsg, zap.Error(err))
+	return status.Error(codes.Internal, internalErrorMsg)
+}
 
-	wrapups := make([]*pb.Wrapup, 0, result.TotalHits())
-	for _, hit := range result.Hits.Hits {
-		var wrapup pb.Wrapup
-		if err := json.Unmarshal(*hit.Source, &wrapup); err != nil {
-			errMsg := "failed to Unmarshal response to JSON"
-			s.logger.Error(errMsg, zap.Error(err))
-			return nil, status.Error(codes.Internal, internalErrorMsg)
+// ListWrapups returns the list of wrapup document.
+// Wrapup documents in the trash are not included.
+func (s *WrapupsServer) ListWrapups(ctx context.Context, req *pb.ListWrapupsRequest) (*pb.ListWrapupsResponse, error) {
+	expr, err := parseFilter(req.Filter)
+	if err != nil {
+		s.logger.Error("invalid filter", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	exprs := []filterExpr{expr}
+	if req.Author != "" {
+		exprs = append(exprs, &compareExpr{field: filterFields["author"], op: ":", text: req.Author})
+	}
+	if req.Venue != "" {
+		exprs = append(exprs, &compareExpr{field: filterFields["venue"], op: ":", text: req.Venue})
+	}
+	if req.Year != 0 {
+		exprs = append(exprs, &compareExpr{field: filterFields["year"], op: "=", number: int64(req.Year)})
+	}
+	for _, tag := range normalizeTags(req.Tags) {
+		exprs = append(exprs, &compareExpr{field: filterFields["tag"], op: "=", text: tag})
+	}
+	if req.CreatedBy != "" {
+		createdBy := req.CreatedBy
+		if createdBy == "mine" {
+			createdBy, _ = auth.UserFromContext(ctx)
+			if createdBy == "" {
+				errMsg := "created_by \"mine\" requires authenticated user"
+				s.logger.Error(errMsg)
+				return nil, status.Error(codes.InvalidArgument, errMsg)
+			}
 		}
-		wrapup.Id = hit.Id
-		wrapups = append(wrapups, &wrapup)
+		exprs = append(exprs, &compareExpr{field: filterFields["created_by"], op: "=", text: createdBy})
+	}
+
+	orders, err := parseOrderBy(req.OrderBy)
+	if err != nil {
+		s.logger.Error("invalid order_by", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	query := &ListQuery{
+		Filter:    newFilter(exprs...),
+		OrderBy:   orders,
+		PageSize:  pageSize(req.PageSize),
+		PageToken: req.PageToken,
 	}
+	res, err := s.store.List(ctx, query)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to get documents")
+	}
+	return res, nil
+}
 
-	return &pb.ListWrapupsResponse{
-		Count:   int32(result.TotalHits()),
-		Wrapups: wrapups,
-	}, nil
+// ListDeletedWrapups returns the list of wrapup document in the trash.
+func (s *WrapupsServer) ListDeletedWrapups(ctx context.Context, req *pb.ListDeletedWrapupsRequest) (*pb.ListWrapupsResponse, error) {
+	expr, err := parseFilter(req.Filter)
+	if err != nil {
+		s.logger.Error("invalid filter", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	orders, err := parseOrderBy("")
+	if err != nil {
+		s.logger.Error("invalid order_by", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	query := &ListQuery{
+		Deleted:   true,
+		Filter:    newFilter(expr),
+		OrderBy:   orders,
+		PageSize:  pageSize(req.PageSize),
+		PageToken: req.PageToken,
+	}
+	res, err := s.store.List(ctx, query)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to get documents")
+	}
+	return res, nil
 }
 
 // GetWrapup returns a wrapup document matched to request.
+// Wrapup documents in the trash are treated as not found.
 func (s *WrapupsServer) GetWrapup(ctx context.Context, req *pb.GetWrapupRequest) (*pb.Wrapup, error) {
 	if req.Id == "" {
 		errMsg := "Id is required"
@@ -162,57 +366,296 @@ func (s *WrapupsServer) GetWrapup(ctx context.Context, req *pb.GetWrapupRequest)
 		return nil, status.Error(codes.InvalidArgument, errMsg)
 	}
 
-	result, err := s.client.Get().Index(s.index).Id(req.Id).Do(ctx)
+	doc, err := s.getWrapup(ctx, req.Id)
 	if err != nil {
-		if elastic.IsNotFound(err) {
-			errMsg := fmt.Sprintf("ID %s not found", req.Id)
-			return nil, status.Error(codes.NotFound, errMsg)
-		}
-		errMsg := "failed to get document from Elasticsearch"
-		s.logger.Error(errMsg, zap.Error(err))
-		return nil, status.Error(codes.Internal, internalErrorMsg)
+		return nil, err
+	}
+	if doc.DeleteTime != nil {
+		errMsg := fmt.Sprintf("ID %s not found", req.Id)
+		return nil, status.Error(codes.NotFound, errMsg)
 	}
+	return doc, nil
+}
 
-	doc := &pb.Wrapup{}
-	if err := json.Unmarshal(*result.Source, doc); err != nil {
-		errMsg := "failed to Unmarshal response to JSON"
-		s.logger.Error(errMsg, zap.Error(err))
-		return nil, status.Error(codes.Internal, internalErrorMsg)
+// getWrapup returns a wrapup document which has given ID, including the one in the trash.
+// Returned error is already converted to gRPC status.
+func (s *WrapupsServer) getWrapup(ctx context.Context, id string) (*pb.Wrapup, error) {
+	doc, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, s.storeError(err, fmt.Sprintf("ID %s not found", id), "failed to get document")
 	}
-	doc.Id = result.Id
 	return doc, nil
 }
 
-// CreateWrapup creates new wrapup document and stores it in Elasticsearch.
+// CreateWrapup creates new wrapup document and stores it.
 func (s *WrapupsServer) CreateWrapup(ctx context.Context, req *pb.CreateWrapupRequest) (*pb.Wrapup, error) {
 	if req.Title == "" {
 		errMsg := "Title is required"
 		s.logger.Error(errMsg)
 		return nil, status.Error(codes.InvalidArgument, errMsg)
 	}
-	// TODO: check if document which has requested title has already exist
-	// if so, return codes.AlreadyExists
-
-	r := struct {
-		pb.CreateWrapupRequest
-		CreateTime *timestamp.Timestamp `json:"create_time"`
-	}{
-		*req,
-		ptypes.TimestampNow(),
+	if err := validateMetadata(req.Metadata); err != nil {
+		s.logger.Error(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	res, err := s.client.Index().Index(s.index).Type(typ).BodyJson(r).Do(ctx)
-	if err != nil {
-		errMsg := "failed to create new document"
-		s.logger.Error(errMsg, zap.Error(err))
-		return nil, status.Error(codes.Internal, internalErrorMsg)
+	// locked regardless of AllowDuplicate so that other requests do not miss the created document
+	unlock := s.titleLocks.lock(req.Title)
+	defer unlock()
+	if !req.AllowDuplicate {
+		if err := s.checkDuplicateTitle(ctx, req.Title); err != nil {
+			return nil, err
+		}
 	}
+
+	user, _ := auth.UserFromContext(ctx)
 	doc := &pb.Wrapup{
-		Id:         res.Id,
 		Title:      req.Title,
 		Wrapup:     req.Wrapup,
 		Comment:    req.Comment,
 		Note:       req.Note,
-		CreateTime: r.CreateTime,
+		CreateTime: ptypes.TimestampNow(),
+		Metadata:   req.Metadata,
+		Tags:       normalizeTags(req.Tags),
+		CreatedBy:  user,
+		UpdatedBy:  user,
+	}
+	doc, err := s.store.Create(ctx, doc)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to create new document")
+	}
+	return doc, nil
+}
+
+// UpdateWrapup updates the fields of existing wrapup document specified by update_mask.
+// If update_mask is not set, all updatable fields are replaced with the given values.
+// If the title is changed, duplicates are checked same as CreateWrapup unless allow_duplicate is set.
+func (s *WrapupsServer) UpdateWrapup(ctx context.Context, req *pb.UpdateWrapupRequest) (*pb.Wrapup, error) {
+	if req.Wrapup == nil || req.Wrapup.Id == "" {
+		errMsg := "Wrapup.Id is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	paths := updatableFields
+	if req.UpdateMask != nil && len(req.UpdateMask.Paths) > 0 {
+		paths = req.UpdateMask.Paths
+	}
+	for _, path := range paths {
+		if !applyField(&pb.Wrapup{}, req.Wrapup, path) {
+			errMsg := fmt.Sprintf("field \"%s\" cannot be updated", path)
+			s.logger.Error(errMsg)
+			return nil, status.Error(codes.InvalidArgument, errMsg)
+		}
 	}
+	if err := validateMetadata(req.Wrapup.Metadata); err != nil {
+		s.logger.Error(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	current, err := s.getWrapup(ctx, req.Wrapup.Id)
+	if err != nil {
+		return nil, err
+	}
+	if current.DeleteTime != nil {
+		errMsg := fmt.Sprintf("ID %s not found", req.Wrapup.Id)
+		return nil, status.Error(codes.NotFound, errMsg)
+	}
+	if req.Wrapup.Version != 0 && req.Wrapup.Version != current.Version {
+		errMsg := fmt.Sprintf("ID %s has been updated since version %d. please get it and try again", req.Wrapup.Id, req.Wrapup.Version)
+		return nil, status.Error(codes.Aborted, errMsg)
+	}
+	updated := proto.Clone(current).(*pb.Wrapup)
+	for _, path := range paths {
+		applyField(updated, req.Wrapup, path)
+	}
+	if updated.Title == "" {
+		errMsg := "Title is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	if normalizeTitle(updated.Title) != normalizeTitle(current.Title) {
+		// same as CreateWrapup, locked regardless of AllowDuplicate
+		unlock := s.titleLocks.lock(updated.Title)
+		defer unlock()
+		if !req.AllowDuplicate {
+			if err := s.checkDuplicateTitle(ctx, updated.Title); err != nil {
+				return nil, err
+			}
+		}
+	}
+	updated.UpdateTime = ptypes.TimestampNow()
+	updated.UpdatedBy, _ = auth.UserFromContext(ctx)
+
+	doc, err := s.updateWrapup(ctx, updated)
+	if err != nil {
+		return nil, err
+	}
+	s.saveRevision(ctx, current)
 	return doc, nil
 }
+
+// updateWrapup replaces the stored wrapup document with wrapup and returns the updated document.
+// Returned error is already converted to gRPC status.
+func (s *WrapupsServer) updateWrapup(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error) {
+	doc, err := s.store.Update(ctx, wrapup)
+	if err != nil {
+		if errors.Cause(err) == ErrConflict {
+			errMsg := fmt.Sprintf("ID %s is being updated concurrently. please try again", wrapup.Id)
+			return nil, status.Error(codes.Aborted, errMsg)
+		}
+		return nil, s.storeError(err, fmt.Sprintf("ID %s not found", wrapup.Id), "failed to update document")
+	}
+	return doc, nil
+}
+
+// DeleteWrapup moves a wrapup document to the trash.
+// Deleted documents are purged permanently after the purge period.
+func (s *WrapupsServer) DeleteWrapup(ctx context.Context, req *pb.DeleteWrapupRequest) (*pb.Wrapup, error) {
+	if req.Id == "" {
+		errMsg := "Id is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	current, err := s.getWrapup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if current.DeleteTime != nil {
+		errMsg := fmt.Sprintf("ID %s not found", req.Id)
+		return nil, status.Error(codes.NotFound, errMsg)
+	}
+	current.DeleteTime = ptypes.TimestampNow()
+	return s.updateWrapup(ctx, current)
+}
+
+// UndeleteWrapup restores a wrapup document from the trash.
+func (s *WrapupsServer) UndeleteWrapup(ctx context.Context, req *pb.UndeleteWrapupRequest) (*pb.Wrapup, error) {
+	if req.Id == "" {
+		errMsg := "Id is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	current, err := s.getWrapup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if current.DeleteTime == nil {
+		errMsg := fmt.Sprintf("ID %s is not deleted", req.Id)
+		return nil, status.Error(codes.FailedPrecondition, errMsg)
+	}
+	current.DeleteTime = nil
+	return s.updateWrapup(ctx, current)
+}
+
+// purgeDeletedWrapups removes the wrapup documents which have been in the trash longer than period.
+// This method runs until the process exits, so it should be called in its own goroutine.
+func (s *WrapupsServer) purgeDeletedWrapups(period time.Duration) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := s.store.Purge(context.Background(), time.Now().Add(-period))
+		if isUnavailable(err) {
+			// retried at the next interval
+			s.logger.Warn("failed to purge deleted documents", zap.Error(err))
+		} else if err != nil {
+			s.logger.Error("failed to purge deleted documents", zap.Error(err))
+		} else if purged > 0 {
+			s.logger.Info(fmt.Sprintf("purged %d deleted documents", purged))
+		}
+		<-ticker.C
+	}
+}
+
+// updatableFields is the list of fields which are replaced when update_mask is not set.
+var updatableFields = []string{"title", "wrapup", "comment", "note", "metadata", "tags"}
+
+// applyField copies the field specified by path from src to dst.
+// false is returned if the field does not exist or cannot be updated.
+func applyField(dst, src *pb.Wrapup, path string) bool {
+	switch path {
+	case "title":
+		dst.Title = src.Title
+	case "wrapup":
+		dst.Wrapup = src.Wrapup
+	case "comment":
+		dst.Comment = src.Comment
+	case "note":
+		dst.Note = src.Note
+	case "metadata":
+		dst.Metadata = src.Metadata
+	case "tags":
+		dst.Tags = normalizeTags(src.Tags)
+	default:
+		if !strings.HasPrefix(path, "metadata.") {
+			return false
+		}
+		return applyMetadataField(dst, src, strings.TrimPrefix(path, "metadata."))
+	}
+	return true
+}
+
+// applyMetadataField copies the metadata field specified by name from src to dst.
+// false is returned if the field does not exist.
+func applyMetadataField(dst, src *pb.Wrapup, name string) bool {
+	from := src.Metadata
+	if from == nil {
+		from = &pb.PaperMetadata{}
+	}
+	to := dst.Metadata
+	if to == nil {
+		to = &pb.PaperMetadata{}
+	} else {
+		to = proto.Clone(to).(*pb.PaperMetadata)
+	}
+	switch name {
+	case "authors":
+		to.Authors = from.Authors
+	case "venue":
+		to.Venue = from.Venue
+	case "year":
+		to.Year = from.Year
+	case "doi":
+		to.Doi = from.Doi
+	case "arxiv_id":
+		to.ArxivId = from.ArxivId
+	case "url":
+		to.Url = from.Url
+	case "abstract":
+		to.Abstract = from.Abstract
+	default:
+		return false
+	}
+	dst.Metadata = to
+	return true
+}
+
+// minYear is the oldest publication year accepted in metadata.
+const minYear = 1000
+
+var (
+	// doiPattern matches DOI like 10.1145/3190508.3190510.
+	doiPattern = regexp.MustCompile(`^10\.[0-9]{4,9}/\S+$`)
+	// arxivIDPattern matches arXiv identifier in both new (1706.03762v5) and old (hep-th/9901001) schemes.
+	arxivIDPattern = regexp.MustCompile(`^([0-9]{4}\.[0-9]{4,5}|[a-z-]+(\.[A-Z]{2})?/[0-9]{7})(v[0-9]+)?$`)
+)
+
+// validateMetadata checks whether metadata has valid values.
+// Empty values are not checked because all fields are optional.
+func validateMetadata(metadata *pb.PaperMetadata) error {
+	if metadata == nil {
+		return nil
+	}
+	// papers may be published with the next year in advance
+	if maxYear := int32(time.Now().Year() + 1); metadata.Year != 0 && (metadata.Year < minYear || metadata.Year > maxYear) {
+		return errors.Errorf("Metadata.Year must be between %d and %d", minYear, maxYear)
+	}
+	if metadata.Doi != "" && !doiPattern.MatchString(metadata.Doi) {
+		return errors.New("Metadata.Doi must be DOI like 10.1145/3190508.3190510")
+	}
+	if metadata.ArxivId != "" && !arxivIDPattern.MatchString(metadata.ArxivId) {
+		return errors.New("Metadata.ArxivId must be arXiv identifier like 1706.03762 or hep-th/9901001")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package wuserver
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	next := int32(time.Now().Year() + 1)
+	tests := []struct {
+		name     string
+		metadata *pb.PaperMetadata
+		wantErr  bool
+	}{
+		{"nil", nil, false},
+		{"empty", &pb.PaperMetadata{}, false},
+		{"valid", &pb.PaperMetadata{Year: 2017, Doi: "10.1145/3190508.3190510", ArxivId: "1706.03762"}, false},
+		{"minimum year", &pb.PaperMetadata{Year: minYear}, false},
+		{"next year", &pb.PaperMetadata{Year: next}, false},
+		{"year before minimum", &pb.PaperMetadata{Year: minYear - 1}, true},
+		{"negative year", &pb.PaperMetadata{Year: -2017}, true},
+		{"year after next", &pb.PaperMetadata{Year: next + 1}, true},
+		{"DOI with long registrant", &pb.PaperMetadata{Doi: "10.18653/v1/N19-1423"}, false},
+		{"DOI with URL", &pb.PaperMetadata{Doi: "https://doi.org/10.1145/3190508.3190510"}, true},
+		{"DOI without suffix", &pb.PaperMetadata{Doi: "10.1145/"}, true},
+		{"DOI with space", &pb.PaperMetadata{Doi: "10.1145/3190508 3190510"}, true},
+		{"arXiv ID with version", &pb.PaperMetadata{ArxivId: "1706.03762v5"}, false},
+		{"arXiv ID with 5 digits", &pb.PaperMetadata{ArxivId: "2101.00001"}, false},
+		{"old arXiv ID", &pb.PaperMetadata{ArxivId: "hep-th/9901001"}, false},
+		{"old arXiv ID with subject class", &pb.PaperMetadata{ArxivId: "math.GT/0309136"}, false},
+		{"arXiv ID with prefix", &pb.PaperMetadata{ArxivId: "arXiv:1706.03762"}, true},
+		{"arXiv ID with short number", &pb.PaperMetadata{ArxivId: "1706.037"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetadata(tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMetadata() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package wuserver
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound is returned by Store when the requested document does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by Store when the document is being changed concurrently.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidPageToken is returned by Store when the page token is not the one issued by the Store.
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrUnavailable is returned by Store when the storage backend is temporarily unavailable.
+	ErrUnavailable = errors.New("storage backend unavailable")
+)
+
+// Store is the storage backend of WrapupsServer.
+//
+// Store only stores and retrieves documents. Validation of requests is done by WrapupsServer,
+// so Store can assume that given values are valid.
+// Errors other than the ones defined in this package are treated as internal errors,
+// except network errors which are treated as ErrUnavailable.
+type Store interface {
+	// Create stores new wrapup document and returns it with the assigned ID.
+	// The document must be found by FindByTitle once Create returns.
+	Create(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error)
+	// Get returns the wrapup document which has given ID, including the one in the trash.
+	// Returned document has its current version.
+	Get(ctx context.Context, id string) (*pb.Wrapup, error)
+	// Update replaces the wrapup document which has the same ID with given one and returns it with the new version.
+	// ErrConflict is returned if the version of wrapup is not the current one, i.e. the document has been changed
+	// since it was read.
+	Update(ctx context.Context, wrapup *pb.Wrapup) (*pb.Wrapup, error)
+	// List returns the wrapup documents matched to query.
+	List(ctx context.Context, query *ListQuery) (*pb.ListWrapupsResponse, error)
+	// Purge removes the wrapup documents deleted before deadline and their revisions permanently.
+	// The number of removed documents is returned.
+	Purge(ctx context.Context, deadline time.Time) (int64, error)
+
+	// Search runs full-text search across all text fields of wrapup documents not in the trash.
+	Search(ctx context.Context, query *SearchQuery) (*pb.SearchWrapupsResponse, error)
+	// Related returns at most size wrapup documents not in the trash which are similar to the one which has given ID.
+	Related(ctx context.Context, id string, size int) ([]*pb.RelatedWrapup, error)
+	// SuggestTitles returns at most size wrapup documents not in the trash whose title starts with prefix.
+	SuggestTitles(ctx context.Context, prefix string, size int) ([]*pb.TitleSuggestion, error)
+	// FindByTitle returns the wrapup document not in the trash whose normalized title is the same as given one.
+	FindByTitle(ctx context.Context, title string) (*pb.Wrapup, error)
+	// Aggregate returns the statistics of wrapup documents not in the trash matched to filter.
+	Aggregate(ctx context.Context, filter *Filter, termsSize int) (*pb.AggregateWrapupsResponse, error)
+
+	// Tags returns all tags attached to wrapup documents not in the trash with the number of documents.
+	Tags(ctx context.Context) ([]*pb.Tag, error)
+	// RenameTag renames tag from to to in all wrapup documents and returns the number of updated documents.
+	// Updated documents get the new version, and user and current time as updated_by and update_time.
+	RenameTag(ctx context.Context, from, to, user string) (int64, error)
+	// BackfillOwner sets user to created_by and updated_by of all wrapup documents which do not have them,
+	// and returns the number of updated documents.
+	BackfillOwner(ctx context.Context, user string) (int64, error)
+
+	// SaveRevision stores the given contents of wrapup document as its revision numbered by the version of wrapup.
+	// ErrConflict is returned if the revision already exists.
+	SaveRevision(ctx context.Context, wrapup *pb.Wrapup) error
+	// ListRevisions returns all revisions of the wrapup document ordered by revision number.
+	ListRevisions(ctx context.Context, id string) ([]*pb.WrapupRevision, error)
+	// GetRevision returns the revision of the wrapup document.
+	GetRevision(ctx context.Context, id string, revision int32) (*pb.WrapupRevision, error)
+}
+
+// Reindexer is implemented by the Store whose index can be rebuilt online.
+type Reindexer interface {
+	// Reindex copies all wrapup documents to new index with current schema and switches to it.
+	// If deleteOld is true, old index is deleted after switched. progress is called at each step.
+	Reindex(ctx context.Context, deleteOld bool, progress func(*pb.ReindexProgress)) error
+}
+
+// ListQuery is the condition of Store.List.
+type ListQuery struct {
+	// Deleted selects the wrapup documents in the trash instead of the others.
+	Deleted bool
+	// Filter restricts the documents. nil matches all documents.
+	Filter *Filter
+	// OrderBy is the order of documents. Ties are broken by ID.
+	OrderBy []Order
+	// PageSize is the maximum number of documents returned.
+	PageSize int
+	// PageToken is the next_page_token returned by previous List.
+	PageToken string
+}
+
+// SearchQuery is the condition of Store.Search.
+type SearchQuery struct {
+	// Query is the search query text.
+	Query string
+	// PageSize is the maximum number of documents returned.
+	PageSize int
+	// PageToken is the next_page_token returned by previous Search.
+	PageToken string
+}
@@ -0,0 +1,42 @@
+package wuserver
+
+import (
+	"context"
+	"strings"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultSuggestSize is the number of suggestions returned when not specified.
+	defaultSuggestSize = 5
+	// maxSuggestSize is the maximum number of suggestions.
+	maxSuggestSize = 50
+)
+
+// SuggestTitles returns wrapup documents whose title starts with the given prefix.
+// Wrapup documents in the trash are not included.
+func (s *WrapupsServer) SuggestTitles(ctx context.Context, req *pb.SuggestTitlesRequest) (*pb.SuggestTitlesResponse, error) {
+	if strings.TrimSpace(req.Prefix) == "" {
+		errMsg := "Prefix is required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	size := int(req.Size)
+	if size <= 0 {
+		size = defaultSuggestSize
+	} else if size > maxSuggestSize {
+		size = maxSuggestSize
+	}
+
+	suggestions, err := s.store.SuggestTitles(ctx, req.Prefix, size)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to suggest titles")
+	}
+	return &pb.SuggestTitlesResponse{
+		Suggestions: suggestions,
+	}, nil
+}
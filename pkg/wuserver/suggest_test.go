@@ -0,0 +1,42 @@
+package wuserver
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestESSuggestSource(t *testing.T) {
+	source, err := esSuggestSource("  Attention IS", 3).Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	// the documents in the trash are filtered in the query, so exactly size documents are requested
+	want := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter":   map[string]interface{}{"prefix": map[string]interface{}{"normalized_title": "attention is"}},
+				"must_not": map[string]interface{}{"exists": map[string]interface{}{"field": "delete_time"}},
+			},
+		},
+		"collapse": map[string]interface{}{"field": "normalized_title"},
+		"sort": []interface{}{
+			map[string]interface{}{"title.keyword": map[string]interface{}{"order": "asc"}},
+			map[string]interface{}{"id": map[string]interface{}{"order": "asc"}},
+		},
+		"_source": map[string]interface{}{"includes": []interface{}{"title"}},
+		"size":    float64(3),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("search source = %s", b)
+	}
+}
@@ -0,0 +1,74 @@
+package wuserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mas9612/wrapups/pkg/auth"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxTags is the maximum number of tags returned by ListTags.
+const maxTags = 1000
+
+// ListTags returns all tags attached to wrapup documents with the number of documents.
+// Wrapup documents in the trash are not counted.
+func (s *WrapupsServer) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+	tags, err := s.store.Tags(ctx)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to aggregate tags")
+	}
+	return &pb.ListTagsResponse{
+		Tags: tags,
+	}, nil
+}
+
+// RenameTag renames a tag in all wrapup documents.
+// If the new tag is already used, two tags are merged.
+// Admin privilege is required because the change affects documents of all users.
+func (s *WrapupsServer) RenameTag(ctx context.Context, req *pb.RenameTagRequest) (*pb.RenameTagResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	from := normalizeTag(req.Tag)
+	to := normalizeTag(req.NewTag)
+	if from == "" || to == "" {
+		errMsg := "Tag and NewTag are required"
+		s.logger.Error(errMsg)
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	if from == to {
+		return &pb.RenameTagResponse{}, nil
+	}
+
+	user, _ := auth.UserFromContext(ctx)
+	updated, err := s.store.RenameTag(ctx, from, to, user)
+	if err != nil {
+		return nil, s.storeError(err, "", "failed to rename tag")
+	}
+	return &pb.RenameTagResponse{
+		Updated: updated,
+	}, nil
+}
+
+// normalizeTag returns tag in the form stored in wrapup documents.
+func normalizeTag(tag string) string {
+	return strings.TrimSpace(tag)
+}
+
+// normalizeTags normalizes each tag and removes empty and duplicated tags.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
@@ -0,0 +1,66 @@
+package wuserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mas9612/wrapups/pkg/auth"
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRenameTag(t *testing.T) {
+	s := &WrapupsServer{
+		store:      newMemoryStore(),
+		logger:     zap.NewNop(),
+		titleLocks: newTitleLocks(),
+		admins:     map[string]bool{"admin": true},
+	}
+	ctx := context.Background()
+	tagged, err := s.store.Create(ctx, &pb.Wrapup{Title: "BERT", Tags: []string{"nlp"}, CreatedBy: "alice", UpdatedBy: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	untagged, err := s.store.Create(ctx, &pb.Wrapup{Title: "ResNet", Tags: []string{"vision"}, CreatedBy: "alice", UpdatedBy: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &pb.RenameTagRequest{Tag: "nlp", NewTag: "language"}
+	if _, err := s.RenameTag(auth.NewContext(ctx, "alice"), req); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("err = %v for non-admin user, want PermissionDenied", err)
+	}
+	if doc, err := s.store.Get(ctx, tagged.Id); err != nil || doc.Tags[0] != "nlp" {
+		t.Fatalf("tag is renamed by non-admin user: %v, %v", doc, err)
+	}
+
+	if _, err := s.RenameTag(auth.NewContext(ctx, "admin"), req); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := s.store.Get(ctx, tagged.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Tags) != 1 || doc.Tags[0] != "language" {
+		t.Errorf("tags = %v, want [language]", doc.Tags)
+	}
+	if doc.Version != tagged.Version+1 {
+		t.Errorf("version = %d, want %d", doc.Version, tagged.Version+1)
+	}
+	if doc.UpdatedBy != "admin" || doc.CreatedBy != "alice" {
+		t.Errorf("created_by, updated_by = %s, %s, want alice, admin", doc.CreatedBy, doc.UpdatedBy)
+	}
+	if doc.UpdateTime == nil || doc.UpdateTime.Seconds < tagged.UpdateTime.GetSeconds() {
+		t.Errorf("update_time = %v is not updated from %v", doc.UpdateTime, tagged.UpdateTime)
+	}
+
+	doc, err = s.store.Get(ctx, untagged.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != untagged.Version || doc.UpdatedBy != "alice" {
+		t.Errorf("document without the tag is updated: version %d, updated_by %s", doc.Version, doc.UpdatedBy)
+	}
+}
@@ -0,0 +1,90 @@
+package wuserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/text/unicode/norm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// normalizeTitle returns the title used to check duplicates.
+// Unicode width (e.g. full-width alphabets) and case are folded,
+// and consecutive whitespaces are replaced with one space.
+func normalizeTitle(title string) string {
+	title = strings.ToLower(norm.NFKC.String(title))
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// checkDuplicateTitle returns AlreadyExists error if a wrapup document which has the same normalized title exists.
+// The ID of the existing document is attached to the error details as DuplicateTitle.
+// Wrapup documents in the trash are not checked.
+// Returned error is already converted to gRPC status.
+func (s *WrapupsServer) checkDuplicateTitle(ctx context.Context, title string) error {
+	existing, err := s.store.FindByTitle(ctx, title)
+	if err != nil {
+		if errors.Cause(err) == ErrNotFound {
+			return nil
+		}
+		return s.storeError(err, "", "failed to search documents")
+	}
+
+	errMsg := fmt.Sprintf("wrapup with the same title already exists: %s", existing.Id)
+	st, err := status.New(codes.AlreadyExists, errMsg).WithDetails(&pb.DuplicateTitle{
+		Id:    existing.Id,
+		Title: existing.Title,
+	})
+	if err != nil {
+		s.logger.Error("failed to attach details to status", zap.Error(err))
+		return status.Error(codes.AlreadyExists, errMsg)
+	}
+	return st.Err()
+}
+
+// titleLocks serializes the creation of wrapup documents which have the same normalized title,
+// so that the duplicate check sees the document created just before.
+// This works only within one process, so the duplicate check is not exact with multiple wuserver instances.
+type titleLocks struct {
+	mu    sync.Mutex
+	locks map[string]*titleLock
+}
+
+type titleLock struct {
+	mu sync.Mutex
+	// refs is the number of goroutines holding or waiting for the lock.
+	refs int
+}
+
+func newTitleLocks() *titleLocks {
+	return &titleLocks{locks: make(map[string]*titleLock)}
+}
+
+// lock locks the normalized title and returns the function to unlock it.
+func (l *titleLocks) lock(title string) func() {
+	title = normalizeTitle(title)
+	l.mu.Lock()
+	t, ok := l.locks[title]
+	if !ok {
+		t = &titleLock{}
+		l.locks[title] = t
+	}
+	t.refs++
+	l.mu.Unlock()
+
+	t.mu.Lock()
+	return func() {
+		t.mu.Unlock()
+		l.mu.Lock()
+		t.refs--
+		if t.refs == 0 {
+			delete(l.locks, title)
+		}
+		l.mu.Unlock()
+	}
+}
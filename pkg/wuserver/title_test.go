@@ -0,0 +1,144 @@
+package wuserver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/mas9612/wrapups/pkg/wrapups"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Attention Is All You Need", "attention is all you need"},
+		{"  Attention   Is\tAll You Need ", "attention is all you need"},
+		{"ＢＥＲＴ", "bert"},
+	}
+	for _, tt := range tests {
+		if got := normalizeTitle(tt.title); got != tt.want {
+			t.Errorf("normalizeTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+// slowFindStore delays the result of FindByTitle to widen the window between the duplicate check and Create.
+type slowFindStore struct {
+	Store
+}
+
+func (s *slowFindStore) FindByTitle(ctx context.Context, title string) (*pb.Wrapup, error) {
+	doc, err := s.Store.FindByTitle(ctx, title)
+	time.Sleep(10 * time.Millisecond)
+	return doc, err
+}
+
+func TestCreateWrapupConcurrentDuplicates(t *testing.T) {
+	s := &WrapupsServer{
+		store:      &slowFindStore{newMemoryStore()},
+		logger:     zap.NewNop(),
+		titleLocks: newTitleLocks(),
+	}
+
+	const n = 20
+	results := make(chan codes.Code, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		title := "Attention Is All You Need"
+		if i%2 == 1 {
+			title = "attention is all  you need"
+		}
+		go func() {
+			defer wg.Done()
+			_, err := s.CreateWrapup(context.Background(), &pb.CreateWrapupRequest{Title: title})
+			results <- status.Code(err)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	created := 0
+	for code := range results {
+		switch code {
+		case codes.OK:
+			created++
+		case codes.AlreadyExists:
+		default:
+			t.Errorf("unexpected code %v", code)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d documents are created, want 1", created)
+	}
+	if len(s.titleLocks.locks) != 0 {
+		t.Errorf("%d locks are left", len(s.titleLocks.locks))
+	}
+}
+
+func TestUpdateWrapupDuplicateTitle(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		title          string
+		allowDuplicate bool
+		want           codes.Code
+	}{
+		{"unchanged", "BERT", false, codes.OK},
+		{"unchanged after normalization", "ｂｅｒｔ", false, codes.OK},
+		{"new title", "RoBERTa", false, codes.OK},
+		{"duplicate", "attention is all  you need", false, codes.AlreadyExists},
+		{"duplicate allowed", "Attention Is All You Need", true, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &WrapupsServer{store: newMemoryStore(), logger: zap.NewNop(), titleLocks: newTitleLocks()}
+			existing, err := s.store.Create(ctx, &pb.Wrapup{Title: "Attention Is All You Need"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := s.store.Create(ctx, &pb.Wrapup{Title: "BERT"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := &pb.UpdateWrapupRequest{
+				Wrapup:         &pb.Wrapup{Id: doc.Id, Title: tt.title},
+				UpdateMask:     &field_mask.FieldMask{Paths: []string{"title"}},
+				AllowDuplicate: tt.allowDuplicate,
+			}
+			_, err = s.UpdateWrapup(ctx, req)
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("code = %v, want %v", code, tt.want)
+			}
+			if tt.want == codes.AlreadyExists {
+				var dup *pb.DuplicateTitle
+				for _, detail := range status.Convert(err).Details() {
+					if d, ok := detail.(*pb.DuplicateTitle); ok {
+						dup = d
+					}
+				}
+				if dup == nil || dup.Id != existing.Id {
+					t.Errorf("details = %v, want DuplicateTitle of %s", dup, existing.Id)
+				}
+				got, err := s.store.Get(ctx, doc.Id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Title != "BERT" {
+					t.Errorf("title is changed to %q", got.Title)
+				}
+			}
+			if len(s.titleLocks.locks) != 0 {
+				t.Errorf("%d locks are left", len(s.titleLocks.locks))
+			}
+		})
+	}
+}